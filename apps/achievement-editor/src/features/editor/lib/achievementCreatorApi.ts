@@ -9,6 +9,11 @@ export type FetchedItemData = {
   itemAwardImagePath: string
 }
 
+/** get_hidden_achievement の応答。warnings は保存対象ではなく、取得後に手動補完が必要な項目を示す。 */
+export type HiddenAchievementData = EditAchievementModel & {
+  warnings?: LocalErrorPayload[]
+}
+
 type ApiOptions = {
   backendBaseUrl: string
   fetcher?: typeof fetch
@@ -70,7 +75,7 @@ function mapAuthErrorToLocalError(error: unknown): LocalErrorPayload | null {
 export async function fetchHiddenAchievement(
   params: HiddenAchievementParams,
   options: ApiOptions
-): Promise<EditAchievementModel | LocalErrorPayload> {
+): Promise<HiddenAchievementData | LocalErrorPayload> {
   const fetcher = options.fetcher ?? fetch
   const url = buildApiUrl(
     options.backendBaseUrl,
@@ -104,7 +109,7 @@ export async function fetchHiddenAchievement(
   if (isLocalErrorPayload(payload)) {
     return payload
   }
-  return payload as EditAchievementModel
+  return payload as HiddenAchievementData
 }
 
 /** 目的: get_icon_img を呼び出してiconPathを取得する。副作用: HTTP GET を実行する。前提: URLパラメータが有効である。 */
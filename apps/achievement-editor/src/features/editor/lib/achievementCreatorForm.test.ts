@@ -40,6 +40,17 @@ describe('achievement creator form contract', () => {
     expect(errors.achievementURL).toContain('アチーブメントURLはLodestoneの実績詳細URL形式で入力してください。')
   })
 
+  test('エオルゼアデータベースのアチーブメントURLを受け付ける', () => {
+    const errors = validateAchievementCreatorForm(
+      {
+        ...createBaseFormState(),
+        achievementURL: 'https://jp.finalfantasyxiv.com/lodestone/playguide/db/achievement/a1b2c3/',
+      },
+      { mode: 'fetch' }
+    )
+    expect(errors.achievementURL).toEqual([])
+  })
+
   test('手入力作成時に必須項目不足を検出する', () => {
     const errors = validateAchievementCreatorForm(
       {
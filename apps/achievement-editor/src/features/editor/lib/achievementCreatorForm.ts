@@ -2,6 +2,8 @@ import { type EditAchievementModel } from '../model/types'
 
 const CHARACTER_URL_REGEXP =
   /^https:\/\/(jp|na|eu|fr|de)\.finalfantasyxiv\.com\/lodestone\/character\/[0-9]+\/achievement\/detail\/[0-9a-zA-Z]+\/?$/
+const ACHIEVEMENT_DB_URL_REGEXP =
+  /^https:\/\/(jp|na|eu|fr|de)\.finalfantasyxiv\.com\/lodestone\/playguide\/db\/achievement\/[0-9a-zA-Z]+\/?$/
 const ICON_URL_REGEXP =
   /^https:\/\/img\.finalfantasyxiv\.com\/lds\/pc\/global\/images\/itemicon\/[0-9a-zA-Z]+\/[0-9a-zA-Z]+\.(jpg|png|gif)(\?.*)?$/
 const ITEM_URL_REGEXP =
@@ -83,7 +85,7 @@ function validateAchievementUrl(value: string): string[] {
     return errors
   }
   const normalized = value.split('?')[0]
-  if (!CHARACTER_URL_REGEXP.test(normalized)) {
+  if (!CHARACTER_URL_REGEXP.test(normalized) && !ACHIEVEMENT_DB_URL_REGEXP.test(normalized)) {
     errors.push('アチーブメントURLはLodestoneの実績詳細URL形式で入力してください。')
   }
   return errors
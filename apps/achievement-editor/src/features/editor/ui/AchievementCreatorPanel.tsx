@@ -139,11 +139,17 @@ export function AchievementCreatorPanel({
         onMessage(`取得エラー: ${response.value}`)
         return
       }
+      const { warnings, ...achievement } = response
       onCreated({
-        ...response,
-        id: response.id ?? `hidden-${Date.now()}-${Math.random().toString(16).slice(2)}`,
+        ...achievement,
+        id: achievement.id ?? `hidden-${Date.now()}-${Math.random().toString(16).slice(2)}`,
       })
-      onMessage(`URL取得で「${response.title}」を追加しました。`)
+      const warningText = (warnings ?? []).map((warning) => warning.value).join(' ')
+      onMessage(
+        warningText === ''
+          ? `URL取得で「${achievement.title}」を追加しました。`
+          : `URL取得で「${achievement.title}」を追加しました。${warningText}`
+      )
       resetForm()
     } finally {
       setIsFetchingHidden(false)
@@ -244,10 +250,10 @@ export function AchievementCreatorPanel({
       </CardHeader>
       <CardContent className="space-y-4">
         <section className="space-y-2 rounded-xl border border-[var(--line)] bg-[var(--surface-alt)] p-3">
-          <p className="text-sm font-semibold">キャラクターページからアチーブメントを取得</p>
+          <p className="text-sm font-semibold">Lodestoneからアチーブメントを取得</p>
           <Input
             value={formState.achievementURL}
-            placeholder="https://jp.finalfantasyxiv.com/lodestone/character/.../achievement/detail/..."
+            placeholder="https://jp.finalfantasyxiv.com/lodestone/character/.../achievement/detail/... または .../playguide/db/achievement/..."
             onChange={(event) => setFieldValue('achievementURL', event.currentTarget.value)}
           />
           {errors.achievementURL.map((message) => (
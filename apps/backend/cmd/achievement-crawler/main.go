@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ff14/achievement-backend/internal/lodestone"
+	"github.com/ff14/achievement-backend/internal/masterdata"
+	"github.com/ff14/achievement-backend/internal/storage"
+)
+
+// 目的: エオルゼアデータベースのアチーブメント一覧をカテゴリごとにクロールしマスターデータを保存する。副作用: 外部サイトへHTTPアクセスし、ストレージと状態ファイルへ書き込み、差分の集計を標準出力へ書き出す。前提: -configのlistUrlが空のカテゴリは-menu-urlの絞り込みリンクから導出する。
+func main() {
+	configPath := flag.String("config", "", "route/category to Lodestone achievement list mapping (required)")
+	menuURL := flag.String("menu-url", masterdata.DefaultCategoryMenuURL, "English Lodestone achievement list used to derive empty listUrl entries")
+	statePath := flag.String("state", "achievement-crawler-state.json", "local state file used to resume and diff runs")
+	restart := flag.Bool("restart", false, "discard an interrupted run and start over")
+	storageBackend := flag.String("storage", "local", "storage backend (local or gcs)")
+	saveRootDir := flag.String("out", "./local-storage/forfan-resource", "root directory for -storage=local")
+	bucketName := flag.String("bucket", "forfan-resource", "bucket for -storage=gcs")
+	objectPrefix := flag.String("prefix", "", "object prefix for -storage=gcs")
+	requestsPerSecond := flag.Float64("rps", 1, "requests per second to Lodestone")
+	timeout := flag.Duration("timeout", 15*time.Second, "request timeout")
+	flag.Parse()
+	if strings.TrimSpace(*configPath) == "" {
+		log.Fatalf("-config is required")
+	}
+	config, err := masterdata.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("failed to load config: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	var textStorage masterdata.TextStorage
+	switch strings.ToLower(*storageBackend) {
+	case "local":
+		textStorage, err = storage.NewFileTextStorage(*saveRootDir)
+	case "gcs":
+		textStorage, err = storage.NewGCSStorage(ctx, *bucketName, *objectPrefix)
+	default:
+		log.Fatalf("unsupported -storage: %s", *storageBackend)
+	}
+	if err != nil {
+		log.Fatalf("failed to initialize storage: %v", err)
+	}
+
+	transport := lodestone.NewHostLimitedTransport(http.DefaultTransport, lodestone.HostLimitConfig{MaxConcurrency: 1, RequestsPerSecond: *requestsPerSecond})
+	httpClient := &http.Client{Timeout: *timeout, Transport: transport, CheckRedirect: lodestone.CheckRedirect}
+	fetcher := lodestone.NewRetryingFetcher(lodestone.NewHTTPFetcher(httpClient), lodestone.RetryConfig{
+		MaxAttempts: 3,
+		BaseDelay:   time.Second,
+		MaxDelay:    30 * time.Second,
+	})
+	client := lodestone.NewHTTPClient(fetcher, nil)
+	sources, err := masterdata.ResolveListURLs(ctx, client, *menuURL, config.Categories)
+	if err != nil {
+		log.Fatalf("failed to derive list urls: %v", err)
+	}
+	crawler := masterdata.NewCrawler(client, textStorage, *statePath)
+	summary, err := crawler.Run(ctx, sources, *restart)
+	if summary != nil {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		_ = encoder.Encode(summary)
+	}
+	if err != nil {
+		log.Fatalf("crawl stopped (rerun to resume): %v", err)
+	}
+	if failed := summary.Failed(); failed > 0 {
+		log.Fatalf("%d categories failed (rerun to resume)", failed)
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/ff14/achievement-backend/internal/lodestone"
+)
+
+// 目的: 各リージョンのLodestoneページを取得しゴールデンテスト用のフィクスチャとして保存する。副作用: 外部サイトへHTTPアクセスしファイルを書き込む。前提: フリーカンパニー所属の公開キャラクターと、女性の公開キャラクターのIDを指定する。
+func main() {
+	characterID := flag.String("character", "", "Lodestone character ID belonging to a free company (required)")
+	femaleCharacterID := flag.String("female-character", "", "Lodestone character ID of a female character for gendered race and job names (required)")
+	achievementID := flag.String("achievement", "", "achievement ID for the achievement detail page")
+	itemID := flag.String("item", "", "item ID for the Eorzea Database item page")
+	dbAchievementID := flag.String("db-achievement", "", "Eorzea Database achievement ID with title and item rewards (defaults to the first row of the achievement list)")
+	regions := flag.String("regions", "jp,na,eu,fr,de", "comma separated Lodestone regions")
+	outDir := flag.String("out", "internal/lodestone/testdata/fixtures", "output directory")
+	interval := flag.Duration("interval", time.Second, "wait between requests")
+	timeout := flag.Duration("timeout", 15*time.Second, "request timeout")
+	flag.Parse()
+	if strings.TrimSpace(*characterID) == "" || strings.TrimSpace(*femaleCharacterID) == "" {
+		log.Fatalf("-character and -female-character are required")
+	}
+
+	recorder := &fixtureRecorder{
+		fetcher:  lodestone.NewHTTPFetcher(&http.Client{Timeout: *timeout, CheckRedirect: lodestone.CheckRedirect}),
+		interval: *interval,
+	}
+	ctx := context.Background()
+	for _, region := range strings.Split(*regions, ",") {
+		region = strings.TrimSpace(region)
+		if region == "" {
+			continue
+		}
+		regionDir := filepath.Join(*outDir, region)
+		if err := os.MkdirAll(regionDir, 0o755); err != nil {
+			log.Fatalf("failed to create %s: %v", regionDir, err)
+		}
+		profileURL := lodestone.BuildCharacterProfileURL(region, *characterID)
+		pages := lodestone.CharacterFixturePages(profileURL)
+		pages = append(pages, lodestone.FemaleCharacterFixturePages(lodestone.BuildCharacterProfileURL(region, *femaleCharacterID))...)
+		if *achievementID != "" {
+			pages = append(pages, lodestone.AchievementDetailFixturePage(profileURL, *achievementID))
+		}
+		if *itemID != "" {
+			pages = append(pages, lodestone.ItemDetailFixturePage(region, *itemID))
+		}
+		pages = append(pages, lodestone.AchievementDBListFixturePage(region))
+		// キャラクターページ・アチーブメント一覧の記録後に辿り先のページが追加されるため、長さを毎回評価する。
+		for index := 0; index < len(pages); index++ {
+			page := pages[index]
+			body, err := recorder.record(ctx, regionDir, page)
+			if err != nil {
+				log.Fatalf("failed to record %s/%s: %v", region, page.Name, err)
+			}
+			switch page.Name {
+			case "character":
+				fcPages := freeCompanyPages(profileURL, body)
+				if len(fcPages) == 0 {
+					log.Fatalf("character %s does not belong to a free company in %s", *characterID, region)
+				}
+				pages = append(pages, fcPages...)
+			case "db_achievement_list":
+				if *dbAchievementID != "" {
+					pages = append(pages, lodestone.AchievementDBDetailFixturePage(region, *dbAchievementID))
+				} else {
+					pages = append(pages, achievementDBDetailPages(region, body)...)
+				}
+			}
+		}
+	}
+}
+
+type fixtureRecorder struct {
+	fetcher  lodestone.Fetcher
+	interval time.Duration
+	fetched  int
+}
+
+// 目的: 1ページを取得し`{name}.html`として保存する。副作用: 外部サイトへHTTPアクセスしファイルを書き込む。前提: Lodestoneへの負荷を避けるため2件目以降はintervalだけ待つ。
+func (r *fixtureRecorder) record(ctx context.Context, regionDir string, page lodestone.FixturePage) ([]byte, error) {
+	if r.fetched > 0 {
+		time.Sleep(r.interval)
+	}
+	r.fetched++
+	fetched, err := r.fetcher.Fetch(ctx, page.URL)
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(regionDir, page.Name+".html")
+	if err := os.WriteFile(path, fetched.Body, 0o644); err != nil {
+		return nil, err
+	}
+	log.Printf("recorded %s (%d bytes) from %s", path, len(fetched.Body), page.URL)
+	return fetched.Body, nil
+}
+
+// 目的: キャラクターページから所属フリーカンパニーを辿り記録対象へ追加する。副作用: なし。前提: 未所属の場合は空スライスを返す。
+func freeCompanyPages(profileURL string, characterBody []byte) []lodestone.FixturePage {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(characterBody))
+	if err != nil {
+		return nil
+	}
+	fcPath := lodestone.ParseFreeCompanyPath(doc)
+	if fcPath == "" {
+		return nil
+	}
+	fcURL, err := lodestone.ResolveFreeCompanyURL(profileURL, fcPath)
+	if err != nil {
+		return nil
+	}
+	return lodestone.FreeCompanyFixturePages(fcURL)
+}
+
+// 目的: アチーブメント一覧の先頭行の詳細ページを記録対象へ追加する。副作用: なし。前提: 一覧を解析できない場合は空スライスを返す。
+func achievementDBDetailPages(region string, listBody []byte) []lodestone.FixturePage {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(listBody))
+	if err != nil {
+		return nil
+	}
+	list, err := lodestone.ParseAchievementDBList(doc)
+	if err != nil || len(list.Entries) == 0 {
+		return nil
+	}
+	return []lodestone.FixturePage{lodestone.AchievementDBDetailFixturePage(region, list.Entries[0].ID)}
+}
@@ -2,16 +2,21 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/ff14/achievement-backend/internal/api"
 	"github.com/ff14/achievement-backend/internal/auth"
+	"github.com/ff14/achievement-backend/internal/jobcatalog"
+	"github.com/ff14/achievement-backend/internal/lodestone"
 	"github.com/ff14/achievement-backend/internal/storage"
 )
 
@@ -25,6 +30,8 @@ func main() {
 	adminFrontOrigin := strings.TrimSpace(os.Getenv("ADMIN_FRONT_ORIGIN"))
 	saveTextRatePerMinute := parseInt(getEnv("SAVE_TEXT_RATE_LIMIT_PER_MINUTE", "20"), 20)
 	getRatePerMinute := parseInt(getEnv("GET_RATE_LIMIT_PER_MINUTE", "60"), 60)
+	persistCharacterImages := parseBool(getEnv("ENABLE_CHARACTER_IMAGE_PERSISTENCE", "false"))
+	maxConcurrencyPerHost := parseInt(getEnv("LODESTONE_MAX_CONCURRENCY_PER_HOST", "4"), 4)
 
 	tokenValidator, err := buildTokenValidator(ctx)
 	if err != nil {
@@ -34,13 +41,33 @@ func main() {
 	if err != nil {
 		log.Fatalf("failed to initialize text storage: %v", err)
 	}
+	jobCatalog, err := jobcatalog.Load(os.Getenv("JOB_CATALOG_PATH"))
+	if err != nil {
+		log.Fatalf("failed to load job catalog: %v", err)
+	}
+	selectorCatalogPath := os.Getenv("LODESTONE_SELECTOR_CATALOG_PATH")
+	selectorCatalog, err := lodestone.LoadSelectorCatalog(selectorCatalogPath)
+	if err != nil {
+		log.Fatalf("failed to load selector catalog: %v", err)
+	}
+	lodestone.SetSelectorCatalog(selectorCatalog)
+	watchSelectorCatalogReload(selectorCatalogPath)
+	lodestoneFetcher, err := buildLodestoneFetcher(requestTimeout, maxConcurrencyPerHost)
+	if err != nil {
+		log.Fatalf("failed to initialize lodestone fetcher: %v", err)
+	}
 
 	server := api.NewServer(api.Config{
-		StrictJSONValidation:  strictJSONValidation,
-		ErrorMode:             errorMode,
-		RequestTimeout:        requestTimeout,
-		SaveTextRatePerMinute: saveTextRatePerMinute,
-		GetRatePerMinute:      getRatePerMinute,
+		StrictJSONValidation:   strictJSONValidation,
+		ErrorMode:              errorMode,
+		RequestTimeout:         requestTimeout,
+		SaveTextRatePerMinute:  saveTextRatePerMinute,
+		GetRatePerMinute:       getRatePerMinute,
+		JobCatalog:             jobCatalog,
+		LodestoneClient:        lodestone.NewHTTPClient(lodestoneFetcher, jobCatalog),
+		PersistCharacterImages: persistCharacterImages,
+		// 一括取得の並行数を送信上限に揃え、超過分がTransportの待ち行列に積まれないようにする。
+		HiddenAchievementBatchConcurrency: maxConcurrencyPerHost,
 	}, tokenValidator, textStorage)
 
 	handler := withCORS(server.Handler(), adminFrontOrigin)
@@ -89,6 +116,77 @@ func buildTextStorage(ctx context.Context) (api.TextStorage, error) {
 	}
 }
 
+// 目的: 環境変数に応じてLodestone取得のFetcherを組み立てる。副作用: キャッシュディレクトリを作成する。前提: 送信はホストごとに制限し、キャッシュ未命中時の同時取得は常にまとめて一時的な失敗は再試行し、障害が続くホストは遮断してキャッシュの期限切れエントリで応答する。LODESTONE_CACHE_ENTRIESが0の場合はキャッシュしない。
+func buildLodestoneFetcher(requestTimeout time.Duration, maxConcurrencyPerHost int) (lodestone.Fetcher, error) {
+	transport := lodestone.NewHostLimitedTransport(http.DefaultTransport, lodestone.HostLimitConfig{
+		MaxConcurrency:    maxConcurrencyPerHost,
+		RequestsPerSecond: parseFloat(getEnv("LODESTONE_REQUESTS_PER_SECOND_PER_HOST", "5"), 5),
+	})
+	go logOutboundStats(transport, time.Minute)
+	httpClient := &http.Client{Timeout: requestTimeout, Transport: transport, CheckRedirect: lodestone.CheckRedirect}
+	var fetcher lodestone.Fetcher = lodestone.NewCoalescingFetcher(lodestone.NewHTTPFetcher(httpClient))
+	// 再試行は呼び出し元ごとの期限で打ち切れるよう、同時取得のまとめより外側に置く。
+	fetcher = lodestone.NewRetryingFetcher(fetcher, lodestone.RetryConfig{
+		MaxAttempts: parseInt(getEnv("LODESTONE_RETRY_MAX_ATTEMPTS", "3"), 3),
+		BaseDelay:   time.Duration(parseInt(getEnv("LODESTONE_RETRY_BASE_DELAY_MS", "200"), 200)) * time.Millisecond,
+		MaxDelay:    time.Duration(parseInt(getEnv("LODESTONE_RETRY_MAX_DELAY_MS", "5000"), 5000)) * time.Millisecond,
+	})
+	// 遮断は再試行を終えた1リクエストを1回の失敗として数え、期限切れキャッシュで応答できるようキャッシュより内側に置く。
+	fetcher = lodestone.NewBreakerFetcher(fetcher, lodestone.BreakerConfig{
+		FailureThreshold: parseInt(getEnv("LODESTONE_BREAKER_FAILURE_THRESHOLD", "5"), 5),
+		OpenDuration:     parseSeconds(getEnv("LODESTONE_BREAKER_OPEN_SEC", "30"), 30),
+	})
+	cacheEntries := parseInt(getEnv("LODESTONE_CACHE_ENTRIES", "1024"), 1024)
+	if cacheEntries <= 0 {
+		return fetcher, nil
+	}
+	return lodestone.NewCachingFetcher(fetcher, lodestone.CacheConfig{
+		Capacity: cacheEntries,
+		Dir:      strings.TrimSpace(os.Getenv("LODESTONE_CACHE_DIR")),
+		TTLs: map[lodestone.ResourceKind]time.Duration{
+			lodestone.ResourceCharacter:   parseSeconds(getEnv("LODESTONE_CACHE_TTL_CHARACTER_SEC", "300"), 300),
+			lodestone.ResourceFreeCompany: parseSeconds(getEnv("LODESTONE_CACHE_TTL_FREECOMPANY_SEC", "1800"), 1800),
+			lodestone.ResourceDatabase:    parseSeconds(getEnv("LODESTONE_CACHE_TTL_DATABASE_SEC", "86400"), 86400),
+			lodestone.ResourceImage:       parseSeconds(getEnv("LODESTONE_CACHE_TTL_IMAGE_SEC", "604800"), 604800),
+		},
+	})
+}
+
+// 目的: Lodestoneへの送信待ち行列の状態を定期的にログ出力する。副作用: ログ出力を行う。前提: 前回から送信が無い場合は出力しない。
+func logOutboundStats(transport *lodestone.HostLimitedTransport, interval time.Duration) {
+	lastStarted := map[string]int64{}
+	for range time.Tick(interval) {
+		for _, stats := range transport.Stats() {
+			if stats.Started == lastStarted[stats.Host] && stats.Waiting == 0 {
+				continue
+			}
+			lastStarted[stats.Host] = stats.Started
+			encoded, err := json.Marshal(stats)
+			if err != nil {
+				continue
+			}
+			log.Printf("lodestone outbound stats: %s", encoded)
+		}
+	}
+}
+
+// 目的: SIGHUP受信時にセレクタカタログを再読み込みする。副作用: シグナル待受のgoroutineを起動しログを出力する。前提: 読み込みに失敗した場合は現在のカタログを使い続ける。
+func watchSelectorCatalogReload(path string) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	go func() {
+		for range signals {
+			catalog, err := lodestone.LoadSelectorCatalog(path)
+			if err != nil {
+				log.Printf("failed to reload selector catalog, keeping current one: %v", err)
+				continue
+			}
+			lodestone.SetSelectorCatalog(catalog)
+			log.Printf("selector catalog reloaded (version=%d)", catalog.Version)
+		}
+	}()
+}
+
 // 目的: CORSレスポンスヘッダを付与しOPTIONSプリフライトを処理する。副作用: HTTPヘッダ書き込みを行う。前提: originが空の場合はCORS制限を行わない。
 func withCORS(next http.Handler, origin string) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -97,6 +195,7 @@ func withCORS(next http.Handler, origin string) http.Handler {
 			w.Header().Set("Vary", "Origin")
 			w.Header().Set("Access-Control-Allow-Methods", "GET,POST,OPTIONS")
 			w.Header().Set("Access-Control-Allow-Headers", "Authorization,Content-Type")
+			w.Header().Set("Access-Control-Expose-Headers", "Cache-Status")
 		}
 		if r.Method == http.MethodOptions {
 			w.WriteHeader(http.StatusNoContent)
@@ -130,6 +229,20 @@ func parseInt(value string, fallback int) int {
 	return parsed
 }
 
+// 目的: 文字列数値をfloat64へ変換し失敗時にデフォルト値を返す。副作用: なし。前提: valueは小数表現である可能性がある。
+func parseFloat(value string, fallback float64) float64 {
+	parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
+
+// 目的: 秒数文字列をDurationへ変換し失敗時にデフォルト秒数を返す。副作用: なし。前提: 0以下はキャッシュ無効として扱われる。
+func parseSeconds(value string, fallback int) time.Duration {
+	return time.Duration(parseInt(value, fallback)) * time.Second
+}
+
 // 目的: エラーモード文字列を列挙値へ変換する。副作用: なし。前提: valueはcompatまたはhttpを想定する。
 func parseErrorMode(value string) api.ErrorMode {
 	if strings.EqualFold(strings.TrimSpace(value), string(api.ErrorModeHTTP)) {
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/ff14/achievement-backend/internal/lodestone"
+)
+
+// cacheStatusWriter はレスポンス書き込み開始時にCache-Statusヘッダを付与する。
+type cacheStatusWriter struct {
+	http.ResponseWriter
+	trace       *lodestone.CacheTrace
+	wroteHeader bool
+}
+
+// 目的: ステータス書き込み前にCache-Statusヘッダを設定する。副作用: レスポンスヘッダを更新する。前提: Lodestone取得が1件も無い場合はヘッダを付与しない。
+func (w *cacheStatusWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if value := w.trace.HeaderValue(); value != "" {
+			w.Header().Set("Cache-Status", value)
+		}
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// 目的: WriteHeader未呼び出しのまま本文を書く場合もヘッダを付与する。副作用: レスポンスを書き込む。前提: なし。
+func (w *cacheStatusWriter) Write(body []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(body)
+}
+
+// 目的: Lodestoneを取得するハンドラへキャッシュ判定の記録とCache-Statusヘッダ付与を差し込む。副作用: リクエストコンテキストへCacheTraceを設定する。前提: nextはnilではない。
+func (s *Server) withCacheStatus(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, trace := lodestone.WithCacheTrace(r.Context())
+		next(&cacheStatusWriter{ResponseWriter: w, trace: trace}, r.WithContext(ctx))
+	}
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ff14/achievement-backend/internal/lodestone"
+)
+
+// 目的: キャッシュ付きクライアントで2回目の取得がCache-Statusヘッダでhitと示されることを検証する。副作用: テスト用HTTPサーバと正規表現設定を一時変更する。前提: キャラクター系のTTLを設定する。
+func TestGetCharacterCollection_ReportsCacheStatus(t *testing.T) {
+	var upstreamCalls int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&upstreamCalls, 1)
+		kind := strings.Trim(r.URL.Path[strings.LastIndex(strings.TrimSuffix(r.URL.Path, "/"), "/"):], "/")
+		_, _ = w.Write([]byte(`<li class="` + kind + `__list__item"><img src="/i.png"><span class="` + kind + `__name">A</span></li>`))
+	}))
+	defer mockServer.Close()
+
+	originalProfileRegexp := characterProfileRegexp
+	characterProfileRegexp = regexp.MustCompile(`^` + regexp.QuoteMeta(mockServer.URL) + `/lodestone/character/([0-9]+)$`)
+	defer func() {
+		characterProfileRegexp = originalProfileRegexp
+	}()
+
+	fetcher, err := lodestone.NewCachingFetcher(lodestone.NewHTTPFetcher(mockServer.Client()), lodestone.CacheConfig{
+		Capacity: 8,
+		TTLs:     map[lodestone.ResourceKind]time.Duration{lodestone.ResourceCharacter: time.Minute},
+	})
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	server := NewServer(Config{
+		ErrorMode:       ErrorModeCompat,
+		LodestoneClient: lodestone.NewHTTPClient(fetcher, nil),
+	}, stubAuth{}, &stubStorage{})
+	requestURL := "/api/get_character_collection?url=" + url.QueryEscape(mockServer.URL+"/lodestone/character/12345/")
+
+	first := httptest.NewRecorder()
+	server.Handler().ServeHTTP(first, httptest.NewRequest(http.MethodGet, requestURL, nil))
+	if first.Code != http.StatusOK {
+		t.Fatalf("want status 200, got %d: %s", first.Code, first.Body.String())
+	}
+	if got := first.Header().Get("Cache-Status"); got != `lodestone; fwd=uri-miss; detail="0/2 hit"` {
+		t.Fatalf("want miss cache status, got %q", got)
+	}
+
+	second := httptest.NewRecorder()
+	server.Handler().ServeHTTP(second, httptest.NewRequest(http.MethodGet, requestURL, nil))
+	if got := second.Header().Get("Cache-Status"); !strings.HasPrefix(got, "lodestone; hit; ttl=") {
+		t.Fatalf("want hit cache status, got %q", got)
+	}
+	if calls := atomic.LoadInt32(&upstreamCalls); calls != 2 {
+		t.Fatalf("want 2 upstream calls, got %d", calls)
+	}
+}
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/ff14/achievement-backend/internal/lodestone"
+)
+
+type CharacterCollection struct {
+	CharacterID int                      `json:"characterID"`
+	FetchedDate time.Time                `json:"fetchedDate"`
+	Mounts      lodestone.CollectionList `json:"mounts"`
+	Minions     lodestone.CollectionList `json:"minions"`
+}
+
+// 目的: マウントとミニオンの所持一覧を取得しAPIレスポンスへ変換する。副作用: 外部サイトへHTTPアクセスする。前提: targetURLは正規化済みキャラクターページURLである。
+func (s *Server) fetchCharacterCollection(ctx context.Context, targetURL string, characterID int) (CharacterCollection, error) {
+	collection, err := s.lodestone.FetchCharacterCollection(ctx, targetURL)
+	if err != nil {
+		return CharacterCollection{}, err
+	}
+	return CharacterCollection{
+		CharacterID: characterID,
+		FetchedDate: time.Now().UTC(),
+		Mounts:      collection.Mounts,
+		Minions:     collection.Minions,
+	}, nil
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// 目的: get_character_collectionが認証なしでマウント/ミニオン一覧を返すことを検証する。副作用: テスト用HTTPサーバと正規表現設定を一時変更する。前提: 一覧件数を合計として扱う。
+func TestGetCharacterCollection_ReturnsMountsAndMinions(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/mount/"):
+			_, _ = w.Write([]byte(`<li class="mount__list__item"><img src="/m.png"><span class="mount__name">チョコボ</span></li>`))
+		case strings.HasSuffix(r.URL.Path, "/minion/"):
+			_, _ = w.Write([]byte(`<li class="minion__list__item"><img src="/a.png"><span class="minion__name">ミニオンA</span></li><li class="minion__list__item"><img src="/b.png"><span class="minion__name">ミニオンB</span></li>`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer mockServer.Close()
+
+	originalProfileRegexp := characterProfileRegexp
+	characterProfileRegexp = regexp.MustCompile(`^` + regexp.QuoteMeta(mockServer.URL) + `/lodestone/character/([0-9]+)$`)
+	defer func() {
+		characterProfileRegexp = originalProfileRegexp
+	}()
+
+	server := NewServer(Config{ErrorMode: ErrorModeCompat}, stubAuth{}, &stubStorage{})
+	requestURL := "/api/get_character_collection?url=" + url.QueryEscape(mockServer.URL+"/lodestone/character/12345/")
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, requestURL, nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var collection CharacterCollection
+	if err := json.Unmarshal(rec.Body.Bytes(), &collection); err != nil {
+		t.Fatalf("failed to unmarshal payload: %v", err)
+	}
+	if collection.CharacterID != 12345 || collection.Mounts.Total != 1 || collection.Minions.Total != 2 {
+		t.Fatalf("want id/totals, got %+v", collection)
+	}
+	if collection.Minions.Items[1].Name != "ミニオンB" {
+		t.Fatalf("want minion names, got %+v", collection.Minions.Items)
+	}
+}
+
+// 目的: get_character_collectionがキャラクターページ以外のURLを拒否することを検証する。副作用: なし。前提: 既定のprofile URL正規表現を用いる。
+func TestGetCharacterCollection_InvalidURL(t *testing.T) {
+	server := NewServer(Config{ErrorMode: ErrorModeCompat}, stubAuth{}, &stubStorage{})
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/get_character_collection?url="+url.QueryEscape("https://example.com/"), nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("want status 400, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,27 @@
+package api
+
+import (
+	"context"
+	"fmt"
+)
+
+// 目的: キャラクター画像の格納パスをキャラクターID単位で生成する。副作用: なし。前提: 画像名はLodestoneのハッシュ付きファイル名で、更新時は別名となる。
+func buildCharacterImagePath(characterID int, imageURL string) string {
+	return fmt.Sprintf("characterData/%d/img/%s", characterID, extractLoadstoneImageName(imageURL))
+}
+
+// 目的: キャラクター画像を取得しストレージへ保存して格納パスを返す。副作用: 外部サイトへHTTPアクセスしストレージへ書き込む。前提: imageURLが空の場合は保存せず空文字を返す。
+func (s *Server) persistCharacterImage(ctx context.Context, characterID int, imageURL string) (string, error) {
+	if imageURL == "" {
+		return "", nil
+	}
+	image, err := s.lodestone.FetchImage(ctx, imageURL)
+	if err != nil {
+		return "", err
+	}
+	imagePath := buildCharacterImagePath(characterID, imageURL)
+	if err := s.textStorage.SaveBinary(ctx, imagePath, image.Body, image.ContentType); err != nil {
+		return "", err
+	}
+	return imagePath, nil
+}
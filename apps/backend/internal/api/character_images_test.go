@@ -0,0 +1,92 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/jpeg"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// 目的: キャラクター画像の格納パスがキャラクターID単位となることを検証する。副作用: なし。前提: クエリ文字列は画像名に含めない。
+func TestBuildCharacterImagePath_IsCharacterScoped(t *testing.T) {
+	if path := buildCharacterImagePath(12345, "https://img2.finalfantasyxiv.com/f/abc_96x96.jpg?1700000000"); path != "characterData/12345/img/abc_96x96.jpg" {
+		t.Fatalf("want character scoped path, got %s", path)
+	}
+}
+
+// 目的: 画像保存が有効な場合のみpersistImagesクエリで画像が保存されることを検証する。副作用: テスト用HTTPサーバと正規表現設定を一時変更する。前提: ストレージはスタブで最後の保存結果を記録する。
+func TestGetCharacterInfo_PersistsImagesWhenEnabled(t *testing.T) {
+	var jpegBody bytes.Buffer
+	if err := jpeg.Encode(&jpegBody, image.NewRGBA(image.Rect(0, 0, 1, 1)), nil); err != nil {
+		t.Fatalf("failed to encode jpeg: %v", err)
+	}
+	var mockServer *httptest.Server
+	mockServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, ".jpg"):
+			w.Header().Set("Content-Type", "image/jpeg")
+			_, _ = w.Write(jpegBody.Bytes())
+		case strings.HasSuffix(r.URL.Path, "/class_job/"):
+			_, _ = w.Write([]byte(`<ul class="character__job"><li><div class="character__job__level">90</div><div class="character__job__name">ナイト</div></li></ul>`))
+		case strings.Contains(r.URL.Path, "/achievement/kind/"):
+			_, _ = w.Write([]byte(`<ul class="js--achievements"></ul>`))
+		default:
+			_, _ = w.Write([]byte(`
+<div class="frame__chara__face"><img src="` + mockServer.URL + `/f/face_96x96.jpg?1"></div>
+<div class="frame__chara__name">Test Taro</div>
+<div class="frame__chara__world">Aegis (Elemental)</div>
+<div class="character-block"><div class="character-block__name">ヒューラン<br>ミッドランダー / ♂</div></div>
+<div class="character-block"><div class="character-block__birth">星6月(6月) 17日</div></div>
+<div class="character__detail__image"><img src="` + mockServer.URL + `/f/portrait_640x873.jpg?1"></div>`))
+		}
+	}))
+	defer mockServer.Close()
+
+	originalProfileRegexp := characterProfileRegexp
+	characterProfileRegexp = regexp.MustCompile(`^` + regexp.QuoteMeta(mockServer.URL) + `/lodestone/character/([0-9]+)$`)
+	defer func() {
+		characterProfileRegexp = originalProfileRegexp
+	}()
+
+	requestURL := "/api/get_character_info?persistImages=true&url=" + url.QueryEscape(mockServer.URL+"/lodestone/character/12345")
+	for _, enabled := range []bool{false, true} {
+		storage := &stubStorage{}
+		server := NewServer(Config{
+			ErrorMode:              ErrorModeCompat,
+			PersistCharacterImages: enabled,
+		}, stubAuth{}, storage)
+		rec := httptest.NewRecorder()
+		server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, requestURL, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("want status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var payload struct {
+			CharacterData CharacterProfile `json:"characterData"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+			t.Fatalf("failed to unmarshal payload: %v", err)
+		}
+		profile := payload.CharacterData
+		if profile.PortraitImageURL != mockServer.URL+"/f/portrait_640x873.jpg?1" || profile.FaceImageURL != mockServer.URL+"/f/face_96x96.jpg?1" {
+			t.Fatalf("want image urls, got %s / %s", profile.PortraitImageURL, profile.FaceImageURL)
+		}
+		if !enabled {
+			if storage.savedBinaryPath != "" || profile.PortraitImagePath != "" {
+				t.Fatalf("want no persistence when disabled, got %s", storage.savedBinaryPath)
+			}
+			continue
+		}
+		if profile.PortraitImagePath != "characterData/12345/img/portrait_640x873.jpg" || profile.FaceImagePath != "characterData/12345/img/face_96x96.jpg" {
+			t.Fatalf("want image paths, got %s / %s", profile.PortraitImagePath, profile.FaceImagePath)
+		}
+		if storage.savedBinaryPath != profile.FaceImagePath || storage.savedBinaryContentType != "image/jpeg" {
+			t.Fatalf("want face image saved last, got %s (%s)", storage.savedBinaryPath, storage.savedBinaryContentType)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"github.com/ff14/achievement-backend/internal/jobcatalog"
+	"github.com/ff14/achievement-backend/internal/lodestone"
+)
+
+// 旧契約の`{ level, currentExp?, nextExp? }`。経験値は表示されている場合のみ出力する。
+type JobLevel struct {
+	CurrentExp *int `json:"currentExp,omitempty"`
+	Level      int  `json:"level"`
+	NextExp    *int `json:"nextExp,omitempty"`
+}
+
+// ジョブキーからレベルへの対応。ジョブはカタログで増減するため構造体ではなくマップで保持する。
+type JobLevels map[string]JobLevel
+
+type DPSRole struct {
+	LimitedDPS        JobLevels `json:"limitedDps"`
+	MagicalRangedDPS  JobLevels `json:"magicalRangedDps"`
+	MeleeDPS          JobLevels `json:"meleeDps"`
+	PhysicalRangedDPS JobLevels `json:"physicalRangedDps"`
+}
+
+type BattleRoles struct {
+	DPSRole    DPSRole   `json:"dpsRole"`
+	HealerRole JobLevels `json:"healerRole"`
+	TankRole   JobLevels `json:"tankRole"`
+}
+
+type JobLevelTree struct {
+	BattleRoles BattleRoles
+	Crafter     JobLevels
+	Gatherer    JobLevels
+}
+
+// 目的: 旧契約の`{ level }`形式へレベル情報を変換する。副作用: なし。前提: 経験値は表示されている場合のみ付与する。
+func toLevelValue(level lodestone.JobLevel) JobLevel {
+	value := JobLevel{Level: level.Level}
+	if level.HasExp {
+		currentExp, nextExp := level.CurrentExp, level.NextExp
+		value.CurrentExp = &currentExp
+		value.NextExp = &nextExp
+	}
+	return value
+}
+
+// 目的: カタログに従い旧契約互換のジョブレベル構造（battleRoles/crafter/gatherer）を生成する。副作用: なし。前提: levelsに存在しないジョブは0で初期化する。
+func buildJobLevelTree(catalog *jobcatalog.Catalog, levels map[string]lodestone.JobLevel) JobLevelTree {
+	tree := JobLevelTree{
+		BattleRoles: BattleRoles{
+			DPSRole: DPSRole{
+				LimitedDPS:        JobLevels{},
+				MagicalRangedDPS:  JobLevels{},
+				MeleeDPS:          JobLevels{},
+				PhysicalRangedDPS: JobLevels{},
+			},
+			HealerRole: JobLevels{},
+			TankRole:   JobLevels{},
+		},
+		Crafter:  JobLevels{},
+		Gatherer: JobLevels{},
+	}
+	for _, job := range catalog.Jobs {
+		if group := tree.roleGroup(job.RoleGroup); group != nil {
+			group[job.Key] = toLevelValue(levels[job.Key])
+		}
+	}
+	return tree
+}
+
+// 目的: ロールグループ名に対応するジョブレベルのマップを返す。副作用: なし。前提: 未知のグループはカタログ検証で弾かれるためnilを返す。
+func (t JobLevelTree) roleGroup(roleGroup string) JobLevels {
+	switch roleGroup {
+	case jobcatalog.RoleGroupTank:
+		return t.BattleRoles.TankRole
+	case jobcatalog.RoleGroupHealer:
+		return t.BattleRoles.HealerRole
+	case jobcatalog.RoleGroupMeleeDPS:
+		return t.BattleRoles.DPSRole.MeleeDPS
+	case jobcatalog.RoleGroupPhysicalRangedDPS:
+		return t.BattleRoles.DPSRole.PhysicalRangedDPS
+	case jobcatalog.RoleGroupMagicalRangedDPS:
+		return t.BattleRoles.DPSRole.MagicalRangedDPS
+	case jobcatalog.RoleGroupLimitedDPS:
+		return t.BattleRoles.DPSRole.LimitedDPS
+	case jobcatalog.RoleGroupCrafter:
+		return t.Crafter
+	case jobcatalog.RoleGroupGatherer:
+		return t.Gatherer
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/ff14/achievement-backend/internal/jobcatalog"
+	"github.com/ff14/achievement-backend/internal/lodestone"
+)
+
+// 目的: 経験値表示がある場合のみ旧契約のレベル構造へ経験値を付与することを検証する。副作用: なし。前提: levelsに存在しないジョブは0となる。
+func TestBuildJobLevelTree_IncludesExpOnlyWhenShown(t *testing.T) {
+	tree := buildJobLevelTree(jobcatalog.Default(), map[string]lodestone.JobLevel{
+		"weaver": {Level: 50, CurrentExp: 100, NextExp: 200, HasExp: true},
+	})
+	weaver := tree.Crafter["weaver"]
+	if weaver.Level != 50 || weaver.CurrentExp == nil || *weaver.CurrentExp != 100 || weaver.NextExp == nil || *weaver.NextExp != 200 {
+		t.Fatalf("want weaver level/exp, got %+v", weaver)
+	}
+	carpenter, exists := tree.Crafter["carpenter"]
+	if !exists {
+		t.Fatalf("want carpenter in crafter, got %v", tree.Crafter)
+	}
+	if carpenter.CurrentExp != nil || carpenter.NextExp != nil {
+		t.Fatalf("want no exp for carpenter, got %+v", carpenter)
+	}
+	if carpenter.Level != 0 {
+		t.Fatalf("want carpenter level 0, got %d", carpenter.Level)
+	}
+}
+
+// 目的: カタログ追加ジョブが旧契約の入れ子ロール配下へ配置されることを検証する。副作用: なし。前提: 既定カタログにピクトマンサーとヴァイパーが含まれる。
+func TestBuildJobLevelTree_PlacesCatalogJobsUnderRoleGroups(t *testing.T) {
+	tree := buildJobLevelTree(jobcatalog.Default(), map[string]lodestone.JobLevel{
+		"pictomancer": {Level: 100},
+	})
+	if pictomancer := tree.BattleRoles.DPSRole.MagicalRangedDPS["pictomancer"]; pictomancer.Level != 100 {
+		t.Fatalf("want pictomancer level 100, got %+v", pictomancer)
+	}
+	if _, exists := tree.BattleRoles.DPSRole.MeleeDPS["viper"]; !exists {
+		t.Fatalf("want viper under meleeDps, got %v", tree.BattleRoles.DPSRole.MeleeDPS)
+	}
+}
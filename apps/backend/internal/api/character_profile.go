@@ -0,0 +1,62 @@
+package api
+
+import (
+	"strings"
+
+	"github.com/ff14/achievement-backend/internal/jobcatalog"
+	"github.com/ff14/achievement-backend/internal/lodestone"
+)
+
+// 旧`characterData`契約の型付き表現。旧実装のmapと同一のバイト列を出力するため、フィールドはJSONキーの辞書順で宣言する。
+type CharacterProfile struct {
+	BattleRoles       BattleRoles             `json:"battleRoles"`
+	BirthDay          string                  `json:"birthDay"`
+	BirthMonth        string                  `json:"birthMonth"`
+	CityState         string                  `json:"cityState,omitempty"`
+	Clan              string                  `json:"clan"`
+	Crafter           JobLevels               `json:"crafter"`
+	Datacenter        string                  `json:"datacenter"`
+	FaceImagePath     string                  `json:"faceImagePath,omitempty"`
+	FaceImageURL      string                  `json:"faceImageUrl,omitempty"`
+	FCURLPath         string                  `json:"fcUrlPath,omitempty"`
+	FirstName         string                  `json:"firstName"`
+	Gatherer          JobLevels               `json:"gatherer"`
+	Gender            string                  `json:"gender"`
+	GrandCompany      *lodestone.GrandCompany `json:"grandCompany,omitempty"`
+	Guardian          string                  `json:"guardian,omitempty"`
+	LastName          string                  `json:"lastName"`
+	PortraitImagePath string                  `json:"portraitImagePath,omitempty"`
+	PortraitImageURL  string                  `json:"portraitImageUrl,omitempty"`
+	Race              string                  `json:"race"`
+	SelfIntroduction  *string                 `json:"selfintroduction"`
+	Server            string                  `json:"server"`
+	Title             string                  `json:"title,omitempty"`
+}
+
+// 目的: Lodestoneの解析結果から旧契約のcharacterDataを組み立てる。副作用: なし。前提: 画像の格納パスは保存した場合のみ呼び出し側で設定する。
+func buildCharacterProfile(catalog *jobcatalog.Catalog, character *lodestone.Character) CharacterProfile {
+	page := character.Page
+	jobLevelTree := buildJobLevelTree(catalog, character.JobLevels)
+	return CharacterProfile{
+		FirstName:        page.FirstName,
+		LastName:         page.LastName,
+		SelfIntroduction: emptyTextToNil(strings.TrimSpace(page.SelfIntroduction)),
+		Server:           page.Server,
+		Datacenter:       page.Datacenter,
+		Race:             page.Race,
+		Clan:             page.Clan,
+		Gender:           page.Gender,
+		BirthMonth:       page.BirthMonth,
+		BirthDay:         page.BirthDay,
+		BattleRoles:      jobLevelTree.BattleRoles,
+		Crafter:          jobLevelTree.Crafter,
+		Gatherer:         jobLevelTree.Gatherer,
+		FCURLPath:        page.FreeCompanyPath,
+		Guardian:         page.Guardian,
+		CityState:        page.CityState,
+		GrandCompany:     page.GrandCompany,
+		Title:            page.Title,
+		PortraitImageURL: page.PortraitURL,
+		FaceImageURL:     page.FaceURL,
+	}
+}
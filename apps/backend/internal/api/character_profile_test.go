@@ -0,0 +1,78 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ff14/achievement-backend/internal/jobcatalog"
+	"github.com/ff14/achievement-backend/internal/lodestone"
+)
+
+// 目的: CharacterProfileのJSON出力が旧map実装で生成したゴールデンファイルとバイト単位で一致することを検証する。副作用: testdataを読み込む。前提: ゴールデンは旧実装のMarshalIndent出力である。
+func TestCharacterProfile_MatchesLegacyGoldenJSON(t *testing.T) {
+	introduction := "よろしく<お願い>します & \"quotes\""
+	withFreeCompany := buildJobLevelTree(jobcatalog.Default(), map[string]lodestone.JobLevel{
+		"paladin":     {Level: 100},
+		"pictomancer": {Level: 92, CurrentExp: 1234567, NextExp: 12345678, HasExp: true},
+		"weaver":      {Level: 90, CurrentExp: 0, NextExp: 15000000, HasExp: true},
+		"miner":       {Level: 100},
+	})
+	withoutFreeCompany := buildJobLevelTree(jobcatalog.Default(), map[string]lodestone.JobLevel{})
+	testCases := []struct {
+		golden  string
+		profile CharacterProfile
+	}{
+		{
+			golden: "character_profile_with_freecompany.golden.json",
+			profile: CharacterProfile{
+				FirstName:        "Test",
+				LastName:         "Taro",
+				SelfIntroduction: &introduction,
+				Server:           "Chocobo",
+				Datacenter:       "Mana",
+				Race:             "ヒューラン",
+				Clan:             "ミッドランダー",
+				Gender:           "♂",
+				BirthMonth:       "6",
+				BirthDay:         "17",
+				BattleRoles:      withFreeCompany.BattleRoles,
+				Crafter:          withFreeCompany.Crafter,
+				Gatherer:         withFreeCompany.Gatherer,
+				FCURLPath:        "/lodestone/freecompany/9229001536389012345/",
+			},
+		},
+		{
+			golden: "character_profile_without_freecompany.golden.json",
+			profile: CharacterProfile{
+				FirstName:   "Solo",
+				LastName:    "Player",
+				Server:      "Gilgamesh",
+				Datacenter:  "Aether",
+				Race:        "Miqo'te",
+				Clan:        "Keeper of the Moon",
+				Gender:      "♀",
+				BirthMonth:  "1",
+				BirthDay:    "32",
+				BattleRoles: withoutFreeCompany.BattleRoles,
+				Crafter:     withoutFreeCompany.Crafter,
+				Gatherer:    withoutFreeCompany.Gatherer,
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		want, err := os.ReadFile(filepath.Join("testdata", testCase.golden))
+		if err != nil {
+			t.Fatalf("failed to read golden: %v", err)
+		}
+		got, err := json.MarshalIndent(testCase.profile, "", "  ")
+		if err != nil {
+			t.Fatalf("failed to marshal profile: %v", err)
+		}
+		if !bytes.Equal(append(got, '\n'), want) {
+			t.Fatalf("want golden %s, got:\n%s", testCase.golden, got)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// 1回の一括取得で受け付ける最大件数。パッチ1回分の追加アチーブメント（100件前後）を想定する。
+const maxHiddenAchievementBatchEntries = 200
+
+// HiddenAchievementBatchEntry は一括取得の1件分の指定。各項目はget_hidden_achievementのクエリと同じ意味を持つ。
+type HiddenAchievementBatchEntry struct {
+	URL      string `json:"url"`
+	Category string `json:"category"`
+	Group    string `json:"group"`
+}
+
+// HiddenAchievementBatchLine は一括取得のNDJSON応答の1行。完了順に返すため、Indexでリクエスト配列の位置を示す。ResultとErrorはどちらか一方のみ設定される。
+type HiddenAchievementBatchLine struct {
+	Index  int              `json:"index"`
+	URL    string           `json:"url"`
+	Result *EditAchievement `json:"result,omitempty"`
+	Error  *LocalError      `json:"error,omitempty"`
+}
+
+// 目的: 複数のアチーブメントURLを送信上限内で並行取得し、完了したものから1件1行のNDJSONで返す。副作用: 外部サイトへHTTPアクセスしアイテム画像をストレージへ保存する。前提: 認証とレート制限はwithAuthで1リクエスト分として扱われ、個別の失敗はLocalErrorの行として返し全体は200とする。
+func (s *Server) handleGetHiddenAchievementBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var entries []HiddenAchievementBatchEntry
+	if err := json.NewDecoder(r.Body).Decode(&entries); err != nil {
+		s.respondLocalError(w, http.StatusBadRequest, "invalid_request_body", "request body must be an array of url, category, group")
+		return
+	}
+	if len(entries) == 0 {
+		s.respondLocalError(w, http.StatusBadRequest, "missing_parameter", "at least one entry is required")
+		return
+	}
+	if len(entries) > maxHiddenAchievementBatchEntries {
+		s.respondLocalError(w, http.StatusBadRequest, "too_many_entries", "too many entries in one batch")
+		return
+	}
+
+	ctx := withPatchDefinitionCache(r.Context())
+	lines := make(chan HiddenAchievementBatchLine)
+	go func() {
+		var group errgroup.Group
+		group.SetLimit(s.config.HiddenAchievementBatchConcurrency)
+		for index, entry := range entries {
+			group.Go(func() error {
+				line := HiddenAchievementBatchLine{Index: index, URL: strings.TrimSpace(entry.URL)}
+				result, localErr := s.fetchHiddenAchievementBatchEntry(ctx, entry)
+				if localErr != nil {
+					line.Error = localErr
+				} else {
+					line.Result = &result
+				}
+				lines <- line
+				return nil
+			})
+		}
+		_ = group.Wait()
+		close(lines)
+	}()
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	controller := http.NewResponseController(w)
+	encoder := json.NewEncoder(w)
+	writeFailed := false
+	for line := range lines {
+		// 切断後も取得中の処理が送信でブロックしないよう、書き込みに失敗しても最後まで受け取る。
+		if writeFailed {
+			continue
+		}
+		if err := encoder.Encode(line); err != nil {
+			writeFailed = true
+			continue
+		}
+		_ = controller.Flush()
+	}
+}
+
+// 目的: 一括取得の1件を検証して取得する。副作用: 外部サイトへHTTPアクセスしアイテム画像をストレージへ保存する。前提: 検証と取得の失敗はget_hidden_achievementと同じキーのLocalErrorで返す。
+func (s *Server) fetchHiddenAchievementBatchEntry(ctx context.Context, entry HiddenAchievementBatchEntry) (EditAchievement, *LocalError) {
+	targetURL := strings.TrimSpace(entry.URL)
+	category := strings.TrimSpace(entry.Category)
+	group := strings.TrimSpace(entry.Group)
+	if targetURL == "" || category == "" || group == "" {
+		return EditAchievement{}, &LocalError{Key: "missing_parameter", Value: "url, category, group is required"}
+	}
+	if !characterRegexp.MatchString(targetURL) && !achievementDBRegexp.MatchString(targetURL) {
+		return EditAchievement{}, &LocalError{Key: "invalid_url", Value: "achievement url is invalid"}
+	}
+	result, err := s.fetchHiddenAchievement(ctx, targetURL, category, group)
+	if err != nil {
+		_, localErr := lodestoneErrorResponse(err, "fetch_hidden_achievement_error")
+		return EditAchievement{}, &localErr
+	}
+	return result, nil
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ff14/achievement-backend/internal/apperrors"
+	"github.com/ff14/achievement-backend/internal/lodestone"
+)
+
+// 目的: 一括取得が各件の結果または個別のLocalErrorを1行ずつNDJSONで返すことを検証する。副作用: なし。前提: キャラクター詳細の取得は非公開エラーとなり、エオルゼアデータベースの取得は成功する。
+func TestGetHiddenAchievementBatch_StreamsResultsAndErrors(t *testing.T) {
+	server := NewServer(Config{
+		ErrorMode: ErrorModeHTTP,
+		LodestoneClient: stubLodestone{
+			err: apperrors.ErrAchievementPrivate,
+			dbDetail: &lodestone.AchievementDBDetail{
+				Title:       "未達成の実績",
+				Description: "説明文",
+				IconURL:     "https://img.finalfantasyxiv.com/lds/pc/global/images/itemicon/12/abc.png",
+				Point:       10,
+			},
+		},
+	}, stubAuth{uid: "test-user"}, &stubStorage{})
+	body := `[
+		{"url":"https://jp.finalfantasyxiv.com/lodestone/playguide/db/achievement/a1b2c3/","category":"battle","group":"quests"},
+		{"url":"https://jp.finalfantasyxiv.com/lodestone/character/1/achievement/detail/abc/","category":"battle","group":"quests"},
+		{"url":"https://example.com/","category":"battle","group":"quests"},
+		{"url":"https://jp.finalfantasyxiv.com/lodestone/playguide/db/achievement/d4e5f6/","category":"","group":"quests"}
+	]`
+	req := httptest.NewRequest(http.MethodPost, "/api/get_hidden_achievement_batch", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer test-token")
+	rec := httptest.NewRecorder()
+
+	server.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/x-ndjson" {
+		t.Fatalf("want 200 ndjson, got %d %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	var lines []HiddenAchievementBatchLine
+	scanner := bufio.NewScanner(rec.Body)
+	for scanner.Scan() {
+		var line HiddenAchievementBatchLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("failed to unmarshal line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) != 4 {
+		t.Fatalf("want 4 lines, got %d", len(lines))
+	}
+	sort.Slice(lines, func(i, j int) bool { return lines[i].Index < lines[j].Index })
+	if lines[0].Result == nil || lines[0].Result.Title != "未達成の実績" || lines[0].Error != nil {
+		t.Fatalf("want result for first entry, got %+v", lines[0])
+	}
+	wantKeys := []string{"private_achievement_data", "invalid_url", "missing_parameter"}
+	for index, wantKey := range wantKeys {
+		line := lines[index+1]
+		if line.Result != nil || line.Error == nil || line.Error.Key != wantKey {
+			t.Fatalf("want %s for entry %d, got %+v", wantKey, line.Index, line)
+		}
+	}
+}
+
+// 目的: 一括取得は件数によらずレート制限を1回分だけ消費し、空の指定は400とすることを検証する。副作用: なし。前提: get系の上限を毎分2回に設定する。
+func TestGetHiddenAchievementBatch_CountsAsOneRateLimitUnit(t *testing.T) {
+	server := NewServer(Config{
+		ErrorMode:        ErrorModeHTTP,
+		GetRatePerMinute: 2,
+		LodestoneClient:  stubLodestone{err: apperrors.ErrLodestoneNotFound},
+	}, stubAuth{uid: "test-user"}, &stubStorage{})
+	entry := `{"url":"https://jp.finalfantasyxiv.com/lodestone/character/1/achievement/detail/abc/","category":"battle","group":"quests"}`
+	requests := []struct {
+		body       string
+		wantStatus int
+	}{
+		{body: "[" + strings.Repeat(entry+",", 4) + entry + "]", wantStatus: http.StatusOK},
+		{body: "[]", wantStatus: http.StatusBadRequest},
+		{body: "[" + entry + "]", wantStatus: http.StatusTooManyRequests},
+	}
+	for _, tc := range requests {
+		req := httptest.NewRequest(http.MethodPost, "/api/get_hidden_achievement_batch", strings.NewReader(tc.body))
+		req.Header.Set("Authorization", "Bearer test-token")
+		rec := httptest.NewRecorder()
+
+		server.Handler().ServeHTTP(rec, req)
+
+		if rec.Code != tc.wantStatus {
+			t.Fatalf("want %d, got %d: %s", tc.wantStatus, rec.Code, rec.Body.String())
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const patchDefinitionPath = "patch/patch.json"
+
+// patchDefinition はpatch/patch.jsonの1件のうちパッチ照合に使う項目。numberは`7.1`のようなバージョン表記である。
+type patchDefinition struct {
+	ID     int    `json:"id"`
+	Number string `json:"number"`
+}
+
+// 目的: Lodestoneに表示されたパッチバージョンを保存済みのパッチ定義と照合しパッチIDを返す。副作用: ストレージからpatch/patch.jsonを読み込む。前提: バージョンが取得できなかった場合は従来どおり0を返し警告しない。定義の読込失敗や未登録のバージョンは取得自体を失敗させず警告として返す。
+func (s *Server) resolvePatchID(ctx context.Context, version string) (int, *LocalError) {
+	if version == "" {
+		return 0, nil
+	}
+	patches, err := s.loadPatchDefinitions(ctx)
+	if err != nil {
+		return 0, &LocalError{Key: "patch_definition_unavailable", Value: fmt.Sprintf("パッチ定義を読み込めなかったため、パッチ%sのpatchIdを設定できませんでした。", version)}
+	}
+	if patchID, ok := matchPatchID(patches, version); ok {
+		return patchID, nil
+	}
+	return 0, &LocalError{Key: "patch_not_found", Value: fmt.Sprintf("パッチ%sがパッチ定義に未登録のため、patchIdを設定できませんでした。", version)}
+}
+
+// patchDefinitionCache は一括取得の1リクエスト中にパッチ定義の読込を1回へまとめる。
+type patchDefinitionCache struct {
+	once    sync.Once
+	patches []patchDefinition
+	err     error
+}
+
+type patchDefinitionCacheKey struct{}
+
+// 目的: パッチ定義の読込結果をリクエスト内で共有するキャッシュをコンテキストへ設定する。副作用: なし。前提: 複数件を取得するハンドラの開始時に1回呼ぶ。
+func withPatchDefinitionCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, patchDefinitionCacheKey{}, &patchDefinitionCache{})
+}
+
+// 目的: パッチ定義を読み込み、コンテキストにキャッシュがあれば初回の結果を共有する。副作用: ストレージを読み込む。前提: 読込失敗もキャッシュし同じリクエスト内では再試行しない。
+func (s *Server) loadPatchDefinitions(ctx context.Context) ([]patchDefinition, error) {
+	cache, ok := ctx.Value(patchDefinitionCacheKey{}).(*patchDefinitionCache)
+	if !ok {
+		return s.readPatchDefinitions(ctx)
+	}
+	cache.once.Do(func() {
+		cache.patches, cache.err = s.readPatchDefinitions(ctx)
+	})
+	return cache.patches, cache.err
+}
+
+// 目的: ストレージからパッチ定義を読み込む。副作用: ストレージを読み込む。前提: patch/patch.jsonはパッチ定義の配列である。
+func (s *Server) readPatchDefinitions(ctx context.Context) ([]patchDefinition, error) {
+	body, err := s.textStorage.ReadText(ctx, patchDefinitionPath)
+	if err != nil {
+		return nil, err
+	}
+	var patches []patchDefinition
+	if err := json.Unmarshal(body, &patches); err != nil {
+		return nil, fmt.Errorf("invalid patch definitions: %w", err)
+	}
+	return patches, nil
+}
+
+// 目的: バージョン表記が一致するパッチ定義のIDを返す。副作用: なし。前提: `7.0`と`7.05`は別のパッチとして扱い、前後の空白のみ無視する。
+func matchPatchID(patches []patchDefinition, version string) (int, bool) {
+	for _, patch := range patches {
+		if strings.TrimSpace(patch.Number) == version {
+			return patch.ID, true
+		}
+	}
+	return 0, false
+}
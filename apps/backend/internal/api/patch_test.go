@@ -0,0 +1,36 @@
+package api
+
+import (
+	"context"
+	"testing"
+)
+
+// 目的: 表示パッチとパッチ定義の照合結果と警告キーを検証する。副作用: なし。前提: パッチ定義はstubStorageから読み込む。
+func TestResolvePatchID(t *testing.T) {
+	patchJSON := []byte(`[{"id":70,"number":"7.0","date":"2024-07-02"},{"id":71,"number":"7.05","date":"2024-08-06"},{"id":72,"number":" 7.1 ","date":"2024-11-12"}]`)
+	cases := []struct {
+		name        string
+		texts       map[string][]byte
+		version     string
+		wantID      int
+		wantWarning string
+	}{
+		{name: "no version", texts: map[string][]byte{patchDefinitionPath: patchJSON}, version: ""},
+		{name: "exact match", texts: map[string][]byte{patchDefinitionPath: patchJSON}, version: "7.05", wantID: 71},
+		{name: "trimmed number", texts: map[string][]byte{patchDefinitionPath: patchJSON}, version: "7.1", wantID: 72},
+		{name: "not registered", texts: map[string][]byte{patchDefinitionPath: patchJSON}, version: "7.2", wantWarning: "patch_not_found"},
+		{name: "missing definitions", texts: nil, version: "7.0", wantWarning: "patch_definition_unavailable"},
+		{name: "invalid definitions", texts: map[string][]byte{patchDefinitionPath: []byte(`{}`)}, version: "7.0", wantWarning: "patch_definition_unavailable"},
+	}
+	for _, tc := range cases {
+		server := NewServer(Config{}, stubAuth{uid: "test-user"}, &stubStorage{texts: tc.texts})
+		patchID, warning := server.resolvePatchID(context.Background(), tc.version)
+		gotWarning := ""
+		if warning != nil {
+			gotWarning = warning.Key
+		}
+		if patchID != tc.wantID || gotWarning != tc.wantWarning {
+			t.Fatalf("%s: want %d %q, got %d %q", tc.name, tc.wantID, tc.wantWarning, patchID, gotWarning)
+		}
+	}
+}
@@ -30,6 +30,11 @@ func (r *statusRecorder) WriteHeader(status int) {
 	r.ResponseWriter.WriteHeader(status)
 }
 
+// 目的: ストリーミング応答のFlush等でhttp.ResponseControllerが元のResponseWriterへ到達できるようにする。副作用: なし。前提: なし。
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
 // 目的: リクエストIDを付与し構造化ログを出力するミドルウェアを提供する。副作用: ヘッダ追加とログ出力を行う。前提: nextはnilではない。
 func (s *Server) withRequestLogging(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -7,7 +7,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"net"
 	"net/http"
 	"net/url"
@@ -16,8 +15,9 @@ import (
 	"strings"
 	"time"
 
-	"github.com/PuerkitoBio/goquery"
 	"github.com/ff14/achievement-backend/internal/apperrors"
+	"github.com/ff14/achievement-backend/internal/jobcatalog"
+	"github.com/ff14/achievement-backend/internal/lodestone"
 )
 
 var (
@@ -26,6 +26,7 @@ var (
 	patchPathRegexp             = regexp.MustCompile(`^patch/patch\.json$`)
 	characterProfileRegexp      = regexp.MustCompile(`^https://(jp|na|eu|fr|de).finalfantasyxiv.com/lodestone/character/([0-9]+)/?$`)
 	characterRegexp             = regexp.MustCompile(`^https://(jp|na|eu|fr|de).finalfantasyxiv.com/lodestone/character/[0-9]+/achievement/detail/[0-9a-zA-Z]+/?$`)
+	achievementDBRegexp         = regexp.MustCompile(`^https://(jp|na|eu|fr|de).finalfantasyxiv.com/lodestone/playguide/db/achievement/[0-9a-zA-Z]+/?$`)
 	itemRegexp                  = regexp.MustCompile(`^https://(jp|na|eu|fr|de).finalfantasyxiv.com/lodestone/playguide/db/item/[0-9a-zA-Z]+/?$`)
 	iconRegexp                  = regexp.MustCompile(`^https://img.finalfantasyxiv.com/lds/pc/global/images/itemicon/[0-9a-zA-Z]+/[0-9a-zA-Z]+\.(jpg|png|gif)(\?.*)?$`)
 )
@@ -43,6 +44,13 @@ type Config struct {
 	RequestTimeout        time.Duration
 	SaveTextRatePerMinute int
 	GetRatePerMinute      int
+	JobCatalog            *jobcatalog.Catalog
+	// 未指定の場合はRequestTimeoutを適用したHTTPクライアントで生成する。
+	LodestoneClient lodestone.Client
+	// 公開APIからのストレージ書き込みとなるため、persistImagesクエリは本設定が有効な場合のみ受け付ける。
+	PersistCharacterImages bool
+	// get_hidden_achievement_batchで同時に取得する件数。Lodestoneへのホストごとの同時送信数に合わせる。0以下の場合は4。
+	HiddenAchievementBatchConcurrency int
 }
 
 type TokenValidator interface {
@@ -52,6 +60,7 @@ type TokenValidator interface {
 type TextStorage interface {
 	SaveText(ctx context.Context, path string, body []byte) error
 	SaveBinary(ctx context.Context, path string, body []byte, contentType string) error
+	ReadText(ctx context.Context, path string) ([]byte, error)
 }
 
 type LocalError struct {
@@ -72,21 +81,12 @@ type SaveTextResponse struct {
 }
 
 type ResponseData struct {
-	CharacterID               int                         `json:"characterID"`
-	FetchedDate               time.Time                   `json:"fetchedDate"`
-	CharacterData             map[string]any              `json:"characterData"`
-	CompletedAchievementsKind []CompletedAchievementsKind `json:"completedAchievementsKinds"`
-	IsAchievementPrivate      bool                        `json:"isAchievementPrivate"`
-}
-
-type CompletedAchievementsKind struct {
-	Key          string                 `json:"key"`
-	Achievements []CompletedAchievement `json:"achievements"`
-}
-
-type CompletedAchievement struct {
-	Title         string    `json:"title"`
-	CompletedDate time.Time `json:"completedDate"`
+	CharacterID               int                                   `json:"characterID"`
+	FetchedDate               time.Time                             `json:"fetchedDate"`
+	CharacterData             CharacterProfile                      `json:"characterData"`
+	CompletedAchievementsKind []lodestone.CompletedAchievementsKind `json:"completedAchievementsKinds"`
+	IsAchievementPrivate      bool                                  `json:"isAchievementPrivate"`
+	FreecompanyInfo           *lodestone.FreeCompany                `json:"freecompanyInfo,omitempty"`
 }
 
 type EditAchievement struct {
@@ -112,6 +112,8 @@ type EditAchievement struct {
 	ItemAwardImagePath string   `json:"itemAwardImagePath,omitempty"`
 	AwardCondition     []string `json:"awardCondition,omitempty"`
 	URL                string   `json:"url,omitempty"`
+	// 保存対象ではない取得時の注意事項。パッチ未登録等、取得は成功したが手動での補完が必要な場合に設定する。
+	Warnings []LocalError `json:"warnings,omitempty"`
 }
 
 type FetchedItemData struct {
@@ -127,7 +129,8 @@ type Server struct {
 	textStorage    TextStorage
 	rateLimiter    RateLimiter
 	mux            *http.ServeMux
-	httpClient     *http.Client
+	lodestone      lodestone.Client
+	jobCatalog     *jobcatalog.Catalog
 }
 
 // 目的: APIサーバの依存関係とルーティングを初期化する。副作用: ルーティングテーブルを構築する。前提: tokenValidatorとtextStorageがnilではない。
@@ -146,15 +149,25 @@ func NewServer(config Config, tokenValidator TokenValidator, textStorage TextSto
 	}
 	config.SaveTextRatePerMinute = saveTextRatePerMinute
 	config.GetRatePerMinute = getRatePerMinute
+	if config.HiddenAchievementBatchConcurrency <= 0 {
+		config.HiddenAchievementBatchConcurrency = 4
+	}
+	catalog := config.JobCatalog
+	if catalog == nil {
+		catalog = jobcatalog.Default()
+	}
+	lodestoneClient := config.LodestoneClient
+	if lodestoneClient == nil {
+		lodestoneClient = lodestone.NewHTTPClient(lodestone.NewHTTPFetcher(&http.Client{Timeout: timeout, CheckRedirect: lodestone.CheckRedirect}), catalog)
+	}
 	server := &Server{
 		config:         config,
 		tokenValidator: tokenValidator,
 		textStorage:    textStorage,
 		rateLimiter:    NewInMemoryRateLimiter(saveTextRatePerMinute, getRatePerMinute),
 		mux:            http.NewServeMux(),
-		httpClient: &http.Client{
-			Timeout: timeout,
-		},
+		lodestone:      lodestoneClient,
+		jobCatalog:     catalog,
 	}
 	server.routes()
 	return server
@@ -167,11 +180,14 @@ func (s *Server) Handler() http.Handler {
 
 // 目的: APIエンドポイントを登録する。副作用: ServeMuxへハンドラを設定する。前提: サーバ初期化処理中に1回だけ呼ばれる。
 func (s *Server) routes() {
-	s.mux.HandleFunc("/api/get_character_info", s.handleGetCharacterInfo)
+	s.mux.HandleFunc("/api/get_character_info", s.withCacheStatus(s.handleGetCharacterInfo))
+	s.mux.HandleFunc("/api/get_character_collection", s.withCacheStatus(s.handleGetCharacterCollection))
+	s.mux.HandleFunc("/api/get_job_catalog", s.handleGetJobCatalog)
 	s.mux.HandleFunc("/api/save_text", s.withAuth(s.handleSaveText))
-	s.mux.HandleFunc("/api/get_hidden_achievement", s.withAuth(s.handleGetHiddenAchievement))
-	s.mux.HandleFunc("/api/get_icon_img", s.withAuth(s.handleGetIconImg))
-	s.mux.HandleFunc("/api/get_item_infomation", s.withAuth(s.handleGetItemInfomation))
+	s.mux.HandleFunc("/api/get_hidden_achievement", s.withAuth(s.withCacheStatus(s.handleGetHiddenAchievement)))
+	s.mux.HandleFunc("/api/get_hidden_achievement_batch", s.withAuth(s.handleGetHiddenAchievementBatch))
+	s.mux.HandleFunc("/api/get_icon_img", s.withAuth(s.withCacheStatus(s.handleGetIconImg)))
+	s.mux.HandleFunc("/api/get_item_infomation", s.withAuth(s.withCacheStatus(s.handleGetItemInfomation)))
 }
 
 // 目的: 公開のキャラクター取得API契約に従いLodestoneページから基本情報を返す。副作用: 外部サイトへHTTPアクセスしレート制限カウンタを更新する。前提: urlクエリはLodestoneのキャラクターページURLである。
@@ -184,28 +200,75 @@ func (s *Server) handleGetCharacterInfo(w http.ResponseWriter, r *http.Request)
 		http.Error(w, "too many requests", http.StatusTooManyRequests)
 		return
 	}
+	normalizedURL, characterID, ok := parseCharacterProfileQuery(w, r)
+	if !ok {
+		return
+	}
+	persistImages := s.config.PersistCharacterImages && r.URL.Query().Get("persistImages") == "true"
+	responseData, err := s.fetchCharacterInfo(r.Context(), normalizedURL, characterID, persistImages)
+	if err != nil {
+		status, localErr := lodestoneErrorResponse(err, "fetch_character_error")
+		writeJSON(w, status, localErr)
+		return
+	}
+	writeJSON(w, http.StatusOK, responseData)
+}
+
+// 目的: キャラクターの所持マウント/ミニオン一覧を返す。副作用: 外部サイトへHTTPアクセスしレート制限カウンタを更新する。前提: urlクエリはLodestoneのキャラクターページURLである。
+func (s *Server) handleGetCharacterCollection(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !s.rateLimiter.Allow(publicRequesterKey(r), r.URL.Path) {
+		http.Error(w, "too many requests", http.StatusTooManyRequests)
+		return
+	}
+	normalizedURL, characterID, ok := parseCharacterProfileQuery(w, r)
+	if !ok {
+		return
+	}
+	collection, err := s.fetchCharacterCollection(r.Context(), normalizedURL, characterID)
+	if err != nil {
+		status, localErr := lodestoneErrorResponse(err, "fetch_character_error")
+		writeJSON(w, status, localErr)
+		return
+	}
+	writeJSON(w, http.StatusOK, collection)
+}
+
+// 目的: urlクエリをキャラクターページURLとして検証し正規化URLとキャラクターIDを返す。副作用: 不正時は400レスポンスを書き込む。前提: 公開キャラクターAPIで共通に使う。
+func parseCharacterProfileQuery(w http.ResponseWriter, r *http.Request) (string, int, bool) {
 	rawURL := strings.TrimSpace(r.URL.Query().Get("url"))
 	if rawURL == "" {
 		writeJSON(w, http.StatusBadRequest, LocalError{Key: "url_invalid", Value: "URLを設定してください。"})
-		return
+		return "", 0, false
 	}
 	normalizedURL := normalizeProfileURL(rawURL)
 	matchedProfileURL := characterProfileRegexp.FindStringSubmatch(normalizedURL)
 	if len(matchedProfileURL) < 2 {
 		writeJSON(w, http.StatusBadRequest, LocalError{Key: "url_invalid", Value: "URLはloadstoneのキャラクターページを貼ってください。"})
-		return
+		return "", 0, false
 	}
 	characterID, err := strconv.Atoi(matchedProfileURL[len(matchedProfileURL)-1])
 	if err != nil {
 		writeJSON(w, http.StatusBadRequest, LocalError{Key: "url_invalid", Value: "URLはloadstoneのキャラクターページを貼ってください。"})
+		return "", 0, false
+	}
+	return normalizedURL, characterID, true
+}
+
+// 目的: フロントエンド向けにジョブカタログを返す。副作用: レート制限カウンタを更新する。前提: 認証不要の公開APIである。
+func (s *Server) handleGetJobCatalog(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
-	responseData, err := s.fetchCharacterInfo(r.Context(), normalizedURL, characterID)
-	if err != nil {
-		writeJSON(w, http.StatusBadGateway, LocalError{Key: "fetch_character_error", Value: err.Error()})
+	if !s.rateLimiter.Allow(publicRequesterKey(r), r.URL.Path) {
+		http.Error(w, "too many requests", http.StatusTooManyRequests)
 		return
 	}
-	writeJSON(w, http.StatusOK, responseData)
+	writeJSON(w, http.StatusOK, s.jobCatalog)
 }
 
 // 目的: Bearerトークン必須の認証ミドルウェアを適用する。副作用: 不正認証時にレスポンスを書き込み処理を中断する。前提: AuthorizationヘッダにBearer形式でトークンが渡される。
@@ -292,13 +355,13 @@ func (s *Server) handleGetHiddenAchievement(w http.ResponseWriter, r *http.Reque
 		s.respondLocalError(w, http.StatusBadRequest, "missing_parameter", "url, category, group is required")
 		return
 	}
-	if !characterRegexp.MatchString(targetURL) {
-		s.respondLocalError(w, http.StatusBadRequest, "invalid_url", "character achievement url is invalid")
+	if !characterRegexp.MatchString(targetURL) && !achievementDBRegexp.MatchString(targetURL) {
+		s.respondLocalError(w, http.StatusBadRequest, "invalid_url", "achievement url is invalid")
 		return
 	}
 	result, err := s.fetchHiddenAchievement(r.Context(), targetURL, category, group)
 	if err != nil {
-		s.respondLocalError(w, http.StatusBadGateway, "fetch_hidden_achievement_error", err.Error())
+		s.respondLodestoneError(w, err, "fetch_hidden_achievement_error")
 		return
 	}
 	writeJSON(w, http.StatusOK, result)
@@ -324,12 +387,12 @@ func (s *Server) handleGetIconImg(w http.ResponseWriter, r *http.Request) {
 	}
 	iconName := extractLoadstoneImageName(iconURL)
 	iconPath := fmt.Sprintf("achievementData/img/%s/%s/%s", category, group, iconName)
-	imageBody, contentType, err := s.fetchBinary(r.Context(), iconURL)
+	image, err := s.lodestone.FetchImage(r.Context(), iconURL)
 	if err != nil {
-		s.respondLocalError(w, http.StatusBadGateway, "fetch_icon_image_error", err.Error())
+		s.respondLodestoneError(w, err, "fetch_icon_image_error")
 		return
 	}
-	if err := s.textStorage.SaveBinary(r.Context(), iconPath, imageBody, contentType); err != nil {
+	if err := s.textStorage.SaveBinary(r.Context(), iconPath, image.Body, image.ContentType); err != nil {
 		s.respondLocalError(w, http.StatusInternalServerError, "save_icon_image_error", err.Error())
 		return
 	}
@@ -356,7 +419,7 @@ func (s *Server) handleGetItemInfomation(w http.ResponseWriter, r *http.Request)
 	}
 	data, err := s.fetchItemInfo(r.Context(), itemURL, category, group)
 	if err != nil {
-		s.respondLocalError(w, http.StatusBadGateway, "fetch_item_information_error", err.Error())
+		s.respondLodestoneError(w, err, "fetch_item_information_error")
 		return
 	}
 	writeJSON(w, http.StatusOK, data)
@@ -376,6 +439,26 @@ func (s *Server) respondLocalError(w http.ResponseWriter, status int, key string
 	writeJSON(w, status, LocalError{Key: key, Value: value})
 }
 
+// 目的: Lodestone取得エラーを互換モード設定に応じたLocalErrorレスポンスで返す。副作用: ステータスコードとレスポンスボディを書き込む。前提: 判別できないエラーはfallbackKeyと502で返す。
+func (s *Server) respondLodestoneError(w http.ResponseWriter, err error, fallbackKey string) {
+	status, localErr := lodestoneErrorResponse(err, fallbackKey)
+	s.respondLocalError(w, status, localErr.Key, localErr.Value)
+}
+
+// 目的: Lodestoneのページ無し・アチーブメント非公開・メンテナンスを旧フロントエンドが判別できるキーとHTTPステータスへ変換する。副作用: なし。前提: いずれにも該当しない場合はfallbackKeyと502を返す。
+func lodestoneErrorResponse(err error, fallbackKey string) (int, LocalError) {
+	switch {
+	case errors.Is(err, apperrors.ErrLodestoneNotFound):
+		return http.StatusNotFound, LocalError{Key: "not_found_user", Value: "入力されたIDからユーザーが見つかりませんでした。"}
+	case errors.Is(err, apperrors.ErrAchievementPrivate):
+		return http.StatusForbidden, LocalError{Key: "private_achievement_data", Value: "アチーブメントページがプライベートに設定されています。"}
+	case errors.Is(err, apperrors.ErrLodestoneMaintenance):
+		return http.StatusServiceUnavailable, LocalError{Key: "fetch_ldst_error", Value: "Lodestoneがメンテナンス中のため取得できませんでした。"}
+	default:
+		return http.StatusBadGateway, LocalError{Key: fallbackKey, Value: err.Error()}
+	}
+}
+
 // 目的: JSONレスポンスを共通形式で返す。副作用: レスポンスヘッダとボディを書き込む。前提: bodyはJSONエンコード可能である。
 func writeJSON(w http.ResponseWriter, status int, body any) {
 	w.Header().Set("Content-Type", "application/json")
@@ -383,168 +466,139 @@ func writeJSON(w http.ResponseWriter, status int, body any) {
 	_ = json.NewEncoder(w).Encode(body)
 }
 
-// 目的: Lodestoneページから最低限のアチーブメント情報を抽出する。副作用: 外部サイトへHTTPアクセスする。前提: URLはキャラクターアチーブメント詳細URLである。
+// 目的: Lodestoneページからアチーブメント情報と称号・アイテム・獲得条件の報酬を抽出し、表示パッチからパッチIDを設定する。副作用: 外部サイトへHTTPアクセスしてパッチ定義をストレージから読み込み、報酬アイテムがある場合はアイテム画像をストレージへ保存する。前提: URLはキャラクターアチーブメント詳細URLまたはエオルゼアデータベースのアチーブメントURLである。報酬アイテムのページや画像を取得できない場合は取得自体を失敗させず警告として返す。
 func (s *Server) fetchHiddenAchievement(ctx context.Context, targetURL string, category string, group string) (EditAchievement, error) {
-	htmlBody, err := s.fetchHTML(ctx, targetURL)
-	if err != nil {
-		return EditAchievement{}, err
-	}
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlBody))
+	detail, err := s.fetchAchievementDetail(ctx, targetURL)
 	if err != nil {
 		return EditAchievement{}, err
 	}
-	title := strings.TrimSpace(doc.Find(".db-view__achievement__text__name").First().Text())
-	description := strings.TrimSpace(doc.Find(".db-view__achievement__help").First().Text())
-	iconURL, _ := doc.Find(".db-view__achievement__icon__image").First().Attr("src")
-	pointText := strings.TrimSpace(doc.Find(".db-view__achievement__point").First().Text())
-	point, _ := strconv.Atoi(pointText)
-	if title == "" || description == "" || iconURL == "" {
-		return EditAchievement{}, errors.New("required achievement fields are missing")
-	}
-	iconPath := fmt.Sprintf("achievementData/img/%s/%s/%s", category, group, extractLoadstoneImageName(iconURL))
-	return EditAchievement{
-		Title:             title,
-		Description:       description,
-		IconURL:           iconURL,
+	patchID, patchWarning := s.resolvePatchID(ctx, detail.PatchVersion)
+	iconPath := fmt.Sprintf("achievementData/img/%s/%s/%s", category, group, extractLoadstoneImageName(detail.IconURL))
+	editAchievement := EditAchievement{
+		Title:             detail.Title,
+		Description:       detail.Description,
+		IconURL:           detail.IconURL,
 		IconPath:          iconPath,
-		Point:             point,
-		IsLatestPatch:     doc.Find(".latest_patch__major__icon").Length() > 0,
+		Point:             detail.Point,
+		IsLatestPatch:     detail.IsLatestPatch,
 		IsCreated:         true,
 		IsEdited:          true,
 		IsNowCreated:      true,
 		SourceIndex:       -1,
 		TagIDs:            []int{},
 		AdjustmentPatchID: 0,
-		PatchID:           0,
+		PatchID:           patchID,
+		TitleAward:        detail.Reward.Title,
+		TitleAwardMan:     detail.Reward.TitleMan,
+		TitleAwardWoman:   detail.Reward.TitleWoman,
+		AwardCondition:    detail.Reward.Conditions,
 		URL:               targetURL,
-	}, nil
-}
-
-// 目的: Lodestoneアイテムページから最低限のアイテム情報を抽出する。副作用: 外部サイトへHTTPアクセスする。前提: URLはLodestoneアイテム詳細URLである。
-func (s *Server) fetchItemInfo(ctx context.Context, itemURL string, category string, group string) (FetchedItemData, error) {
-	htmlBody, err := s.fetchHTML(ctx, itemURL)
-	if err != nil {
-		return FetchedItemData{}, err
 	}
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlBody))
-	if err != nil {
-		return FetchedItemData{}, err
-	}
-	name := strings.TrimSpace(doc.Find(".db-view__item__text__name").First().Text())
-	imageURL, exists := doc.Find(".db-view__item__icon__item_image").First().Attr("src")
-	if !exists || imageURL == "" {
-		imageURL, _ = doc.Find("meta[property='og:image']").Attr("content")
+	if patchWarning != nil {
+		editAchievement.Warnings = append(editAchievement.Warnings, *patchWarning)
 	}
-	if name == "" {
-		name = strings.TrimSpace(doc.Find("meta[property='og:title']").AttrOr("content", ""))
+	if detail.Reward.ItemURL != "" {
+		if err := s.applyItemAward(ctx, &editAchievement, targetURL, detail.Reward.ItemURL, category, group); err != nil {
+			// 呼び出し元の取消以外は、パッチ照合と同様にアイテム報酬無しの警告として返す。
+			if ctx.Err() != nil {
+				return EditAchievement{}, err
+			}
+			editAchievement.Warnings = append(editAchievement.Warnings, LocalError{Key: "item_award_unavailable", Value: fmt.Sprintf("報酬アイテムの情報を取得できなかったため、アイテム報酬を設定できませんでした: %v", err)})
+		}
 	}
-	if name == "" || imageURL == "" {
-		return FetchedItemData{}, errors.New("required item fields are missing")
+	return editAchievement, nil
+}
+
+// 目的: URLの形式に応じたページ解析でアチーブメント詳細を取得する。副作用: 外部サイトへHTTPアクセスする。前提: エオルゼアデータベースのページは最新パッチの表示が無いためIsLatestPatchはfalseとなる。
+func (s *Server) fetchAchievementDetail(ctx context.Context, targetURL string) (*lodestone.AchievementDetail, error) {
+	if lodestone.AchievementDBID(targetURL) == "" {
+		return s.lodestone.FetchAchievement(ctx, targetURL)
 	}
-	itemPath := fmt.Sprintf("achievementData/img/%s/%s/item/%s", category, group, extractLoadstoneImageName(imageURL))
-	imageBody, contentType, err := s.fetchBinary(ctx, imageURL)
+	detail, err := s.lodestone.FetchAchievementDBDetail(ctx, targetURL)
 	if err != nil {
-		return FetchedItemData{}, err
-	}
-	if err := s.textStorage.SaveBinary(ctx, itemPath, imageBody, contentType); err != nil {
-		return FetchedItemData{}, err
-	}
-	return FetchedItemData{
-		ItemAward:          name,
-		ItemAwardURL:       itemURL,
-		ItemAwardImageURL:  imageURL,
-		ItemAwardImagePath: itemPath,
+		return nil, err
+	}
+	return &lodestone.AchievementDetail{
+		Title:        detail.Title,
+		Description:  detail.Description,
+		IconURL:      detail.IconURL,
+		Point:        detail.Point,
+		PatchVersion: detail.PatchVersion,
+		Reward:       detail.Reward,
 	}, nil
 }
 
-// 目的: 外部HTMLを取得する共通処理を提供する。副作用: HTTPリクエストを送信する。前提: targetURLはhttp/https形式である。
-func (s *Server) fetchHTML(ctx context.Context, targetURL string) (string, error) {
-	parsedURL, err := url.ParseRequestURI(targetURL)
+// 目的: 報酬アイテムのリンクからアイテム情報を取得し画像を保存して編集データへ設定する。副作用: 外部サイトへHTTPアクセスしアイテム画像をストレージへ保存する。前提: itemHrefはアチーブメント詳細ページ上のリンクで、解決後のURLがitemRegexpに一致しない場合はエラーとする。
+func (s *Server) applyItemAward(ctx context.Context, editAchievement *EditAchievement, pageURL string, itemHref string, category string, group string) error {
+	baseURL, err := url.Parse(pageURL)
 	if err != nil {
-		return "", err
+		return err
 	}
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, parsedURL.String(), nil)
+	itemURL, err := baseURL.Parse(itemHref)
 	if err != nil {
-		return "", err
+		return err
 	}
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		return "", err
+	if !strings.HasSuffix(itemURL.Path, "/") {
+		itemURL.Path += "/"
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return "", fmt.Errorf("failed to fetch html: status=%d", resp.StatusCode)
+	if !itemRegexp.MatchString(itemURL.String()) {
+		return fmt.Errorf("reward item url is invalid: %s", itemURL)
 	}
-	body, err := io.ReadAll(resp.Body)
+	item, err := s.fetchItemInfo(ctx, itemURL.String(), category, group)
 	if err != nil {
-		return "", err
+		return err
 	}
-	return string(body), nil
+	editAchievement.ItemAward = item.ItemAward
+	editAchievement.ItemAwardURL = item.ItemAwardURL
+	editAchievement.ItemAwardImageURL = item.ItemAwardImageURL
+	editAchievement.ItemAwardImagePath = item.ItemAwardImagePath
+	return nil
 }
 
-// 目的: 外部URLからバイナリを取得する共通処理を提供する。副作用: HTTPリクエストを送信する。前提: targetURLはhttp/https形式である。
-func (s *Server) fetchBinary(ctx context.Context, targetURL string) ([]byte, string, error) {
-	parsedURL, err := url.ParseRequestURI(targetURL)
-	if err != nil {
-		return nil, "", err
-	}
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, parsedURL.String(), nil)
+// 目的: Lodestoneアイテムページから最低限のアイテム情報を抽出する。副作用: 外部サイトへHTTPアクセスしアイテム画像をストレージへ保存する。前提: URLはLodestoneアイテム詳細URLである。
+func (s *Server) fetchItemInfo(ctx context.Context, itemURL string, category string, group string) (FetchedItemData, error) {
+	item, err := s.lodestone.FetchItem(ctx, itemURL)
 	if err != nil {
-		return nil, "", err
+		return FetchedItemData{}, err
 	}
-	resp, err := s.httpClient.Do(req)
+	itemPath := fmt.Sprintf("achievementData/img/%s/%s/item/%s", category, group, extractLoadstoneImageName(item.ImageURL))
+	image, err := s.lodestone.FetchImage(ctx, item.ImageURL)
 	if err != nil {
-		return nil, "", err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, "", fmt.Errorf("failed to fetch binary: status=%d", resp.StatusCode)
+		return FetchedItemData{}, err
 	}
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, "", err
+	if err := s.textStorage.SaveBinary(ctx, itemPath, image.Body, image.ContentType); err != nil {
+		return FetchedItemData{}, err
 	}
-	return body, resp.Header.Get("Content-Type"), nil
+	return FetchedItemData{
+		ItemAward:          item.Name,
+		ItemAwardURL:       itemURL,
+		ItemAwardImageURL:  item.ImageURL,
+		ItemAwardImagePath: itemPath,
+	}, nil
 }
 
-// 目的: Lodestoneキャラクターページから旧互換のResponseDataを構築する。副作用: 外部サイトへHTTPアクセスする。前提: targetURLは正規化済みキャラクターページURLである。
-func (s *Server) fetchCharacterInfo(ctx context.Context, targetURL string, characterID int) (ResponseData, error) {
-	htmlBody, err := s.fetchHTML(ctx, targetURL)
-	if err != nil {
-		return ResponseData{}, err
-	}
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlBody))
+// 目的: Lodestoneキャラクターページから旧互換のResponseDataを構築する。副作用: 外部サイトへHTTPアクセスし、persistImages時はキャラクター画像をストレージへ保存する。前提: targetURLは正規化済みキャラクターページURLである。
+func (s *Server) fetchCharacterInfo(ctx context.Context, targetURL string, characterID int, persistImages bool) (ResponseData, error) {
+	character, err := s.lodestone.FetchCharacter(ctx, targetURL)
 	if err != nil {
 		return ResponseData{}, err
 	}
-	nameText := strings.TrimSpace(doc.Find(".frame__chara__name").First().Text())
-	if nameText == "" {
-		return ResponseData{}, errors.New("character name is missing")
-	}
-	firstName, lastName := splitCharacterName(nameText)
-	worldText := strings.TrimSpace(doc.Find(".frame__chara__world").First().Text())
-	server, datacenter := splitServerAndDatacenter(worldText)
-	characterProfile := map[string]any{
-		"firstName":        firstName,
-		"lastName":         lastName,
-		"selfintroduction": emptyTextToNil(strings.TrimSpace(doc.Find(".character__selfintroduction").First().Text())),
-		"server":           server,
-		"datacenter":       datacenter,
-		"race":             "",
-		"clan":             "",
-		"gender":           "",
-		"birthMonth":       "",
-		"birthDay":         "",
-		"battleRoles":      buildDefaultBattleRoles(),
-		"crafter":          buildDefaultCrafter(),
-		"gatherer":         buildDefaultGatherer(),
+	characterProfile := buildCharacterProfile(s.jobCatalog, character)
+	if persistImages {
+		if characterProfile.PortraitImagePath, err = s.persistCharacterImage(ctx, characterID, character.Page.PortraitURL); err != nil {
+			return ResponseData{}, err
+		}
+		if characterProfile.FaceImagePath, err = s.persistCharacterImage(ctx, characterID, character.Page.FaceURL); err != nil {
+			return ResponseData{}, err
+		}
 	}
 	return ResponseData{
 		CharacterID:               characterID,
 		FetchedDate:               time.Now().UTC(),
 		CharacterData:             characterProfile,
-		CompletedAchievementsKind: []CompletedAchievementsKind{},
-		IsAchievementPrivate:      false,
+		CompletedAchievementsKind: character.CompletedAchievementsKinds,
+		IsAchievementPrivate:      character.IsAchievementPrivate,
+		FreecompanyInfo:           character.FreeCompany,
 	}, nil
 }
 
@@ -568,98 +622,12 @@ func normalizeProfileURL(rawURL string) string {
 	return strings.TrimSuffix(withoutQuery, "/")
 }
 
-// 目的: キャラクター名文字列から姓・名を抽出する。副作用: なし。前提: textはLodestone表示名を含む。
-func splitCharacterName(text string) (string, string) {
-	parts := strings.Fields(strings.TrimSpace(text))
-	if len(parts) == 0 {
-		return "", ""
-	}
-	if len(parts) == 1 {
-		return parts[0], ""
-	}
-	return parts[0], strings.Join(parts[1:], " ")
-}
-
-// 目的: ワールド/DC表示文字列からserverとdatacenterを抽出する。副作用: なし。前提: textは`Server (DC)`形式を想定する。
-func splitServerAndDatacenter(text string) (string, string) {
-	trimmed := strings.TrimSpace(text)
-	if trimmed == "" {
-		return "", ""
-	}
-	matched := regexp.MustCompile(`^(.*?)\s*\((.*?)\)$`).FindStringSubmatch(trimmed)
-	if len(matched) == 3 {
-		return strings.TrimSpace(matched[1]), strings.TrimSpace(matched[2])
-	}
-	return trimmed, ""
-}
-
 // 目的: 空文字をnilへ変換して旧契約のnullable文字列に合わせる。副作用: なし。前提: textはtrim済み文字列である。
-func emptyTextToNil(text string) any {
+func emptyTextToNil(text string) *string {
 	if strings.TrimSpace(text) == "" {
 		return nil
 	}
-	return text
-}
-
-// 目的: 旧契約互換の戦闘ジョブレベル初期値を返す。副作用: なし。前提: レベル情報未取得時は0で初期化する。
-func buildDefaultBattleRoles() map[string]any {
-	return map[string]any{
-		"tankRole": map[string]any{
-			"paladin":    map[string]any{"level": 0},
-			"warrior":    map[string]any{"level": 0},
-			"darkKnight": map[string]any{"level": 0},
-			"gunbreaker": map[string]any{"level": 0},
-		},
-		"healerRole": map[string]any{
-			"whiteMage":   map[string]any{"level": 0},
-			"scholar":     map[string]any{"level": 0},
-			"astrologian": map[string]any{"level": 0},
-		},
-		"dpsRole": map[string]any{
-			"meleeDps": map[string]any{
-				"monk":    map[string]any{"level": 0},
-				"dragoon": map[string]any{"level": 0},
-				"ninja":   map[string]any{"level": 0},
-				"samurai": map[string]any{"level": 0},
-			},
-			"physicalRangedDps": map[string]any{
-				"bard":      map[string]any{"level": 0},
-				"machinist": map[string]any{"level": 0},
-				"dancer":    map[string]any{"level": 0},
-			},
-			"magicalRangedDps": map[string]any{
-				"blackMage": map[string]any{"level": 0},
-				"summoner":  map[string]any{"level": 0},
-				"redMage":   map[string]any{"level": 0},
-			},
-			"limitedDps": map[string]any{
-				"blueMage": map[string]any{"level": 0},
-			},
-		},
-	}
-}
-
-// 目的: 旧契約互換のクラフタージョブレベル初期値を返す。副作用: なし。前提: レベル情報未取得時は0で初期化する。
-func buildDefaultCrafter() map[string]any {
-	return map[string]any{
-		"carpenter":     map[string]any{"level": 0},
-		"blacksmith":    map[string]any{"level": 0},
-		"armorer":       map[string]any{"level": 0},
-		"goldsmith":     map[string]any{"level": 0},
-		"leatherworker": map[string]any{"level": 0},
-		"weaver":        map[string]any{"level": 0},
-		"alchemist":     map[string]any{"level": 0},
-		"culinarian":    map[string]any{"level": 0},
-	}
-}
-
-// 目的: 旧契約互換のギャザラージョブレベル初期値を返す。副作用: なし。前提: レベル情報未取得時は0で初期化する。
-func buildDefaultGatherer() map[string]any {
-	return map[string]any{
-		"miner":    map[string]any{"level": 0},
-		"botanist": map[string]any{"level": 0},
-		"fisher":   map[string]any{"level": 0},
-	}
+	return &text
 }
 
 // 目的: Lodestone画像URLからファイル名部分を取り出す。副作用: なし。前提: URLはパス末尾にファイル名を含む。
@@ -4,13 +4,20 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"path/filepath"
 	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/ff14/achievement-backend/internal/apperrors"
+	"github.com/ff14/achievement-backend/internal/lodestone"
 )
 
 type stubAuth struct {
@@ -29,6 +36,7 @@ type stubStorage struct {
 	savedBinaryPath        string
 	savedBinaryBody        []byte
 	savedBinaryContentType string
+	texts                  map[string][]byte
 	err                    error
 	binaryErr              error
 }
@@ -48,6 +56,15 @@ func (s *stubStorage) SaveBinary(_ context.Context, path string, body []byte, co
 	return s.binaryErr
 }
 
+// 目的: 読込処理のテスト差し替えを可能にする。副作用: なし。前提: textsに無いパスは未保存として扱う。
+func (s *stubStorage) ReadText(_ context.Context, path string) ([]byte, error) {
+	body, ok := s.texts[path]
+	if !ok {
+		return nil, apperrors.ErrStorageNotFound
+	}
+	return body, nil
+}
+
 // 目的: save_textの認証必須契約を検証する。副作用: なし。前提: サーバがミドルウェア経由で認証判定する。
 func TestSaveText_Unauthorized(t *testing.T) {
 	server := NewServer(Config{
@@ -184,6 +201,57 @@ func TestFetchHiddenAchievement_ContainsLegacyCompatibleFields(t *testing.T) {
 	}
 }
 
+// 目的: get_hidden_achievementが性別別の称号・獲得条件・報酬アイテムを編集データへ設定し、アイテム画像を保存することを検証する。副作用: テスト用HTTPサーバを起動し正規表現設定を一時変更する。前提: 報酬アイテムのリンクはページ上の相対パスである。
+func TestFetchHiddenAchievement_PopulatesRewards(t *testing.T) {
+	pngBody := testPNGBody(t)
+	var mockServer *httptest.Server
+	mockServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/icon.png":
+			_, _ = w.Write(pngBody)
+		case "/lodestone/playguide/db/item/abc/":
+			_, _ = w.Write([]byte(`<div class="db-view__item__text__name">記念の品</div><img class="db-view__item__icon__item_image" src="` + mockServer.URL + `/icon.png">`))
+		default:
+			_, _ = w.Write([]byte(`
+<div class="db-view__achievement__text__name">テスト実績</div>
+<div class="db-view__achievement__help">説明文</div>
+<img class="db-view__achievement__icon__image" src="https://img.finalfantasyxiv.com/lds/pc/global/images/itemicon/12/abc.png">
+<div class="db-view__achievement__point">10</div>
+<div class="db-view__achievement__reward">
+	<p class="db-view__achievement__reward__title">称号「漆黒の英雄／漆黒の女傑」</p>
+	<p class="db-view__achievement__reward__item"><a href="/lodestone/playguide/db/item/abc">記念の品</a></p>
+	<ul class="db-view__achievement__reward__condition"><li>条件A</li><li>条件B</li></ul>
+</div>`))
+		}
+	}))
+	defer mockServer.Close()
+
+	originalItemRegexp := itemRegexp
+	itemRegexp = regexp.MustCompile(`^` + regexp.QuoteMeta(mockServer.URL) + `/lodestone/playguide/db/item/[0-9a-zA-Z]+/?$`)
+	defer func() {
+		itemRegexp = originalItemRegexp
+	}()
+
+	storage := &stubStorage{}
+	server := NewServer(Config{ErrorMode: ErrorModeCompat}, stubAuth{uid: "test-user"}, storage)
+	result, err := server.fetchHiddenAchievement(context.Background(), mockServer.URL+"/lodestone/character/1/achievement/detail/abc/", "battle", "quests")
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	if result.TitleAward != "" || result.TitleAwardMan != "漆黒の英雄" || result.TitleAwardWoman != "漆黒の女傑" {
+		t.Fatalf("want gendered titles, got %q / %q / %q", result.TitleAward, result.TitleAwardMan, result.TitleAwardWoman)
+	}
+	if strings.Join(result.AwardCondition, ",") != "条件A,条件B" {
+		t.Fatalf("want award conditions, got %v", result.AwardCondition)
+	}
+	if result.ItemAward != "記念の品" || result.ItemAwardURL != mockServer.URL+"/lodestone/playguide/db/item/abc/" {
+		t.Fatalf("want item award, got %s (%s)", result.ItemAward, result.ItemAwardURL)
+	}
+	if result.ItemAwardImagePath != "achievementData/img/battle/quests/item/icon.png" || storage.savedBinaryPath != result.ItemAwardImagePath {
+		t.Fatalf("want item image saved, got %s / %s", result.ItemAwardImagePath, storage.savedBinaryPath)
+	}
+}
+
 // 目的: save_textで権限不足エラー時に403を返すことを検証する。副作用: なし。前提: ストレージが権限不足エラーを返す。
 func TestSaveText_PermissionDenied(t *testing.T) {
 	server := NewServer(Config{
@@ -228,11 +296,22 @@ func TestSaveText_RateLimitExceeded(t *testing.T) {
 	}
 }
 
+// 目的: 画像形式の判定を通過する1x1のPNGを生成する。副作用: なし。前提: テスト用画像サーバの応答に使う。
+func testPNGBody(t *testing.T) []byte {
+	t.Helper()
+	var body bytes.Buffer
+	if err := png.Encode(&body, image.NewRGBA(image.Rect(0, 0, 1, 1))); err != nil {
+		t.Fatalf("failed to encode png: %v", err)
+	}
+	return body.Bytes()
+}
+
 // 目的: fetchItemInfoで画像を保存することを検証する。副作用: テスト用HTTPサーバを起動する。前提: HTMLにアイテム名と画像URLが含まれる。
 func TestFetchItemInfo_SavesImageBinary(t *testing.T) {
+	pngBody := testPNGBody(t)
 	imageServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 		w.Header().Set("Content-Type", "image/png")
-		_, _ = w.Write([]byte("png-binary"))
+		_, _ = w.Write(pngBody)
 	}))
 	defer imageServer.Close()
 
@@ -269,11 +348,84 @@ func TestFetchItemInfo_SavesImageBinary(t *testing.T) {
 	}
 }
 
+// rewriteHostTransport は実際のLodestoneのURLのままテスト用サーバへ送信するTransport。URL検証の正規表現を差し替えずに済む。
+type rewriteHostTransport struct {
+	target *url.URL
+}
+
+// 目的: スキームとホストをテスト用サーバへ置き換えて送信する。副作用: テスト用サーバへHTTPリクエストを送る。前提: パスとクエリは元のURLのまま使う。
+func (t rewriteHostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rewritten := req.Clone(req.Context())
+	rewritten.URL.Scheme = t.target.Scheme
+	rewritten.URL.Host = t.target.Host
+	rewritten.Host = ""
+	return http.DefaultTransport.RoundTrip(rewritten)
+}
+
+// 目的: lodestoneパッケージのリージョン別フィクスチャ（実ページ記録前の手書きページ）のエオルゼアデータベースのアチーブメントページから、報酬とパッチIDを含む編集データを返すことを検証する。副作用: テスト用HTTPサーバを起動しフィクスチャを読み込む。前提: 報酬アイテムのページは同リージョンのdb_item.htmlを返し、画像は全てPNGとする。
+func TestGetHiddenAchievement_AchievementDBFixturePerRegion(t *testing.T) {
+	pngBody := testPNGBody(t)
+	for _, region := range []string{"jp", "na", "eu", "fr", "de"} {
+		t.Run(region, func(t *testing.T) {
+			fixtureDir := filepath.Join("..", "lodestone", "testdata", "fixtures", region)
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case strings.HasPrefix(r.URL.Path, "/lodestone/playguide/db/achievement/"):
+					http.ServeFile(w, r, filepath.Join(fixtureDir, "db_achievement.html"))
+				case strings.HasPrefix(r.URL.Path, "/lodestone/playguide/db/item/"):
+					http.ServeFile(w, r, filepath.Join(fixtureDir, "db_item.html"))
+				default:
+					_, _ = w.Write(pngBody)
+				}
+			}))
+			defer mockServer.Close()
+			mockURL, err := url.Parse(mockServer.URL)
+			if err != nil {
+				t.Fatalf("failed to parse mock url: %v", err)
+			}
+			httpClient := &http.Client{Transport: rewriteHostTransport{target: mockURL}}
+			storage := &stubStorage{texts: map[string][]byte{patchDefinitionPath: []byte(`[{"id":72,"number":"7.1"}]`)}}
+			server := NewServer(Config{
+				ErrorMode:       ErrorModeHTTP,
+				LodestoneClient: lodestone.NewHTTPClient(lodestone.NewHTTPFetcher(httpClient), nil),
+			}, stubAuth{uid: "test-user"}, storage)
+
+			targetURL := "https://" + region + ".finalfantasyxiv.com/lodestone/playguide/db/achievement/0a1b2c3d4e5/"
+			req := httptest.NewRequest(http.MethodGet, "/api/get_hidden_achievement?url="+url.QueryEscape(targetURL)+"&category=battle&group=quests", nil)
+			req.Header.Set("Authorization", "Bearer test-token")
+			rec := httptest.NewRecorder()
+
+			server.Handler().ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("want 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+			var result EditAchievement
+			if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+				t.Fatalf("failed to unmarshal result: %v", err)
+			}
+			if result.Title == "" || result.Description == "" || result.IconPath == "" || result.Point == 0 {
+				t.Fatalf("want achievement fields, got %+v", result)
+			}
+			if result.TitleAward == "" && (result.TitleAwardMan == "" || result.TitleAwardWoman == "") {
+				t.Fatalf("want title reward, got %+v", result)
+			}
+			if result.ItemAward == "" || result.ItemAwardImagePath == "" || storage.savedBinaryPath != result.ItemAwardImagePath {
+				t.Fatalf("want item reward saved, got %+v", result)
+			}
+			if result.PatchID != 72 || len(result.Warnings) != 0 {
+				t.Fatalf("want patch id 72 without warnings, got %d %+v", result.PatchID, result.Warnings)
+			}
+		})
+	}
+}
+
 // 目的: get_icon_imgで画像を保存し返却パスを返すことを検証する。副作用: テスト用HTTPサーバを起動し正規表現設定を一時変更する。前提: iconRegexpがテスト終了時に復元される。
 func TestGetIconImg_SavesBinaryAndReturnsPath(t *testing.T) {
+	pngBody := testPNGBody(t)
 	imageServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 		w.Header().Set("Content-Type", "image/png")
-		_, _ = w.Write([]byte("icon-png"))
+		_, _ = w.Write(pngBody)
 	}))
 	defer imageServer.Close()
 
@@ -351,17 +503,63 @@ func TestGetCharacterInfo_PublicEndpointWithoutAuth(t *testing.T) {
 	mockHTML := `
 <html>
 	<body>
+		<div class="frame__chara__title">エオルゼアの英雄</div>
 		<div class="frame__chara__name">Test Taro</div>
 		<div class="frame__chara__world">Aegis (Elemental)</div>
 		<div class="character-block">
 			<div class="character-block__name">ヒューラン<br>ミッドランダー / ♂</div>
 		</div>
-		<div class="character-block__birth">星6月(6月) 17日</div>
+		<div class="character-block">
+			<div class="character-block__birth">星6月(6月) 17日</div>
+			<div class="character-block__name">アルジク</div>
+		</div>
+		<div class="character-block">
+			<div class="character-block__name">リムサ・ロミンサ</div>
+		</div>
+		<div class="character-block">
+			<img src="https://img.example/gc-rank.png">
+			<div class="character-block__name">黒渦団/大闘佐</div>
+		</div>
 		<div class="character__selfintroduction">hello world</div>
 	</body>
 </html>`
-	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+	mockClassJobHTML := `
+<html>
+	<body>
+		<ul class="character__job">
+			<li>
+				<div class="character__job__level">90</div>
+				<div class="character__job__name" data-tooltip="ナイト / 剣術士">ナイト</div>
+				<div class="character__job__exp">-- / --</div>
+			</li>
+		</ul>
+	</body>
+</html>`
+	mockAchievementKindHTML := `
+<html>
+	<body>
+		<ul class="js--achievements">
+			<li>
+				<div class="entry__achievement entry__achievement--complete">
+					<div class="entry__achievement--history">
+						<p class="entry__activity__txt">テスト実績</p>
+						<time class="entry__activity__time"><script>document.getElementById('datetime-1').innerHTML = ldst_strftime(1600000000, 'YMD');</script></time>
+					</div>
+				</div>
+			</li>
+		</ul>
+	</body>
+</html>`
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if strings.HasSuffix(r.URL.Path, "/class_job/") {
+			_, _ = w.Write([]byte(mockClassJobHTML))
+			return
+		}
+		if strings.Contains(r.URL.Path, "/achievement/kind/") {
+			_, _ = w.Write([]byte(mockAchievementKindHTML))
+			return
+		}
 		_, _ = w.Write([]byte(mockHTML))
 	}))
 	defer mockServer.Close()
@@ -403,6 +601,32 @@ func TestGetCharacterInfo_PublicEndpointWithoutAuth(t *testing.T) {
 	if characterData["lastName"] != "Taro" {
 		t.Fatalf("want lastName=Taro, got %v", characterData["lastName"])
 	}
+	if characterData["race"] != "ヒューラン" || characterData["clan"] != "ミッドランダー" || characterData["gender"] != "♂" {
+		t.Fatalf("want race/clan/gender, got %v/%v/%v", characterData["race"], characterData["clan"], characterData["gender"])
+	}
+	if characterData["birthMonth"] != "6" || characterData["birthDay"] != "17" {
+		t.Fatalf("want nameday 6/17, got %v/%v", characterData["birthMonth"], characterData["birthDay"])
+	}
+	if characterData["guardian"] != "アルジク" || characterData["cityState"] != "リムサ・ロミンサ" || characterData["title"] != "エオルゼアの英雄" {
+		t.Fatalf("want guardian/cityState/title, got %v/%v/%v", characterData["guardian"], characterData["cityState"], characterData["title"])
+	}
+	grandCompany, _ := characterData["grandCompany"].(map[string]any)
+	if grandCompany["name"] != "黒渦団" || grandCompany["rank"] != "大闘佐" || grandCompany["rankIconUrl"] != "https://img.example/gc-rank.png" {
+		t.Fatalf("want grandCompany, got %v", grandCompany)
+	}
+	if _, exists := payload["freecompanyInfo"]; exists {
+		t.Fatalf("want freecompanyInfo omitted for character without free company")
+	}
+	completedKinds, _ := payload["completedAchievementsKinds"].([]any)
+	if len(completedKinds) == 0 {
+		t.Fatalf("want completedAchievementsKinds, got empty")
+	}
+	battleRoles, _ := characterData["battleRoles"].(map[string]any)
+	tankRole, _ := battleRoles["tankRole"].(map[string]any)
+	paladin, _ := tankRole["paladin"].(map[string]any)
+	if paladin["level"] != float64(90) {
+		t.Fatalf("want paladin level 90, got %v", paladin["level"])
+	}
 }
 
 // 目的: get_character_infoのURL必須バリデーションを検証する。副作用: なし。前提: query未指定で呼び出す。
@@ -420,3 +644,164 @@ func TestGetCharacterInfo_MissingURL(t *testing.T) {
 		t.Fatalf("want status 400, got %d", rec.Code)
 	}
 }
+
+// 目的: get_job_catalogが認証なしでカタログを返すことを検証する。副作用: なし。前提: 既定カタログが使われる。
+func TestGetJobCatalog_ReturnsCatalogWithoutAuth(t *testing.T) {
+	server := NewServer(Config{
+		StrictJSONValidation: true,
+		ErrorMode:            ErrorModeCompat,
+	}, stubAuth{uid: "test-user"}, &stubStorage{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/get_job_catalog", nil)
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want status 200, got %d", rec.Code)
+	}
+	var payload struct {
+		Jobs []struct {
+			Key string `json:"key"`
+		} `json:"jobs"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("failed to unmarshal payload: %v", err)
+	}
+	if len(payload.Jobs) == 0 {
+		t.Fatalf("want jobs in catalog, got empty")
+	}
+}
+
+// stubLodestone はアチーブメント詳細の取得のみ固定値を返すClient。他のメソッドは呼ばれない前提で埋め込みのnilに委ねる。
+type stubLodestone struct {
+	lodestone.Client
+	err      error
+	dbDetail *lodestone.AchievementDBDetail
+}
+
+// 目的: 設定されたエラーを返す。副作用: なし。前提: なし。
+func (s stubLodestone) FetchAchievement(_ context.Context, _ string) (*lodestone.AchievementDetail, error) {
+	return nil, s.err
+}
+
+// 目的: 設定されたエオルゼアデータベースの詳細を返す。副作用: なし。前提: dbDetailが無い場合はerrを返す。
+func (s stubLodestone) FetchAchievementDBDetail(_ context.Context, _ string) (*lodestone.AchievementDBDetail, error) {
+	if s.dbDetail == nil {
+		return nil, s.err
+	}
+	return s.dbDetail, nil
+}
+
+// 目的: 設定されたエラーを返す。副作用: なし。前提: なし。
+func (s stubLodestone) FetchItem(_ context.Context, _ string) (*lodestone.ItemDetail, error) {
+	return nil, s.err
+}
+
+// 目的: 報酬アイテムを取得できない場合もアチーブメントを返し、item_award_unavailableの警告を付けることを検証する。副作用: なし。前提: アイテムページの取得はエラーとなる。
+func TestGetHiddenAchievement_WarnsWhenItemAwardUnavailable(t *testing.T) {
+	server := NewServer(Config{
+		ErrorMode: ErrorModeHTTP,
+		LodestoneClient: stubLodestone{
+			err: errors.New("item page unavailable"),
+			dbDetail: &lodestone.AchievementDBDetail{
+				Title:  "報酬付きの実績",
+				Point:  10,
+				Reward: lodestone.AchievementReward{Title: "冒険者", ItemURL: "/lodestone/playguide/db/item/abc/"},
+			},
+		},
+	}, stubAuth{uid: "test-user"}, &stubStorage{})
+	targetURL := "https://jp.finalfantasyxiv.com/lodestone/playguide/db/achievement/a1b2c3/"
+	req := httptest.NewRequest(http.MethodGet, "/api/get_hidden_achievement?url="+url.QueryEscape(targetURL)+"&category=battle&group=quests", nil)
+	req.Header.Set("Authorization", "Bearer test-token")
+	rec := httptest.NewRecorder()
+
+	server.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var result EditAchievement
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatalf("failed to unmarshal result: %v", err)
+	}
+	if result.Title != "報酬付きの実績" || result.TitleAward != "冒険者" || result.ItemAward != "" {
+		t.Fatalf("want achievement without item award, got %+v", result)
+	}
+	if len(result.Warnings) != 1 || result.Warnings[0].Key != "item_award_unavailable" {
+		t.Fatalf("want item_award_unavailable warning, got %+v", result.Warnings)
+	}
+}
+
+// 目的: エオルゼアデータベースのアチーブメントURLを受け付け、キャラクター詳細と同じ編集データ形式で返すことを検証する。副作用: なし。前提: キャラクター詳細の取得はエラーとなるため呼ばれた場合は失敗する。
+func TestGetHiddenAchievement_AcceptsAchievementDBURL(t *testing.T) {
+	server := NewServer(Config{
+		ErrorMode: ErrorModeHTTP,
+		LodestoneClient: stubLodestone{
+			err: errors.New("character page must not be fetched"),
+			dbDetail: &lodestone.AchievementDBDetail{
+				Title:        "未達成の実績",
+				Description:  "説明文",
+				IconURL:      "https://img.finalfantasyxiv.com/lds/pc/global/images/itemicon/12/abc.png",
+				Point:        10,
+				PatchVersion: "7.1",
+				Reward:       lodestone.AchievementReward{Title: "冒険者", Conditions: []string{"条件A"}},
+			},
+		},
+	}, stubAuth{uid: "test-user"}, &stubStorage{texts: map[string][]byte{patchDefinitionPath: []byte(`[{"id":72,"number":"7.1"}]`)}})
+	targetURL := "https://jp.finalfantasyxiv.com/lodestone/playguide/db/achievement/a1b2c3/"
+	req := httptest.NewRequest(http.MethodGet, "/api/get_hidden_achievement?url="+url.QueryEscape(targetURL)+"&category=battle&group=quests", nil)
+	req.Header.Set("Authorization", "Bearer test-token")
+	rec := httptest.NewRecorder()
+
+	server.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var result EditAchievement
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatalf("failed to unmarshal result: %v", err)
+	}
+	if result.Title != "未達成の実績" || result.Point != 10 || result.URL != targetURL || result.IconPath != "achievementData/img/battle/quests/abc.png" {
+		t.Fatalf("want achievement db detail, got %+v", result)
+	}
+	if result.TitleAward != "冒険者" || strings.Join(result.AwardCondition, ",") != "条件A" || result.IsLatestPatch {
+		t.Fatalf("want rewards without latest patch, got %+v", result)
+	}
+	if result.PatchID != 72 || len(result.Warnings) != 0 {
+		t.Fatalf("want patch id 72 without warnings, got %d %+v", result.PatchID, result.Warnings)
+	}
+}
+
+// 目的: Lodestoneのページ無し・非公開・メンテナンスが旧フロントエンドのキーと適切なHTTPステータスで返ることを検証する。副作用: なし。前提: ErrorModeHTTPで動作する。
+func TestGetHiddenAchievement_MapsLodestoneErrors(t *testing.T) {
+	cases := []struct {
+		err        error
+		wantStatus int
+		wantKey    string
+	}{
+		{err: fmt.Errorf("failed to fetch html: %w", apperrors.ErrLodestoneNotFound), wantStatus: http.StatusNotFound, wantKey: "not_found_user"},
+		{err: apperrors.ErrAchievementPrivate, wantStatus: http.StatusForbidden, wantKey: "private_achievement_data"},
+		{err: apperrors.ErrLodestoneMaintenance, wantStatus: http.StatusServiceUnavailable, wantKey: "fetch_ldst_error"},
+		{err: errors.New("boom"), wantStatus: http.StatusBadGateway, wantKey: "fetch_hidden_achievement_error"},
+	}
+	for _, tc := range cases {
+		server := NewServer(Config{
+			ErrorMode:       ErrorModeHTTP,
+			LodestoneClient: stubLodestone{err: tc.err},
+		}, stubAuth{uid: "test-user"}, &stubStorage{})
+		req := httptest.NewRequest(http.MethodGet, "/api/get_hidden_achievement?url=https://jp.finalfantasyxiv.com/lodestone/character/1/achievement/detail/abc/&category=battle&group=quests", nil)
+		req.Header.Set("Authorization", "Bearer test-token")
+		rec := httptest.NewRecorder()
+
+		server.Handler().ServeHTTP(rec, req)
+
+		var localErr LocalError
+		if err := json.Unmarshal(rec.Body.Bytes(), &localErr); err != nil {
+			t.Fatalf("failed to unmarshal local error: %v", err)
+		}
+		if rec.Code != tc.wantStatus || localErr.Key != tc.wantKey {
+			t.Fatalf("want %d %s, got %d %s", tc.wantStatus, tc.wantKey, rec.Code, localErr.Key)
+		}
+	}
+}
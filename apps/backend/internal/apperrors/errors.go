@@ -5,4 +5,12 @@ import "errors"
 var (
 	// 目的: 認可または保存権限不足を示す共通エラーを表す。副作用: なし。前提: errors.Isで判定される。
 	ErrPermissionDenied = errors.New("permission denied")
+	// 目的: Lodestoneがメンテナンス中で取得できないことを表す。副作用: なし。前提: errors.Isで判定される。
+	ErrLodestoneMaintenance = errors.New("lodestone is under maintenance")
+	// 目的: 削除済みキャラクター等、Lodestone上にページが存在しないことを表す。副作用: なし。前提: errors.Isで判定される。
+	ErrLodestoneNotFound = errors.New("lodestone page not found")
+	// 目的: キャラクターのアチーブメントが非公開設定であることを表す。副作用: なし。前提: errors.Isで判定される。
+	ErrAchievementPrivate = errors.New("achievement page is private")
+	// 目的: ストレージ上に読込対象のファイルが存在しないことを表す。副作用: なし。前提: errors.Isで判定される。
+	ErrStorageNotFound = errors.New("storage object not found")
 )
@@ -0,0 +1,170 @@
+package jobcatalog
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// 既定のジョブカタログ。新ジョブ追加時はこのJSONのみを更新する。
+//
+//go:embed job_catalog.json
+var defaultCatalogJSON []byte
+
+// Lodestoneの表示言語。リージョンjpはja、na/euはen、fr/deはそれぞれの言語で表示される。
+var supportedLocales = []string{"ja", "en", "fr", "de"}
+
+const (
+	RoleGroupTank              = "tankRole"
+	RoleGroupHealer            = "healerRole"
+	RoleGroupMeleeDPS          = "meleeDps"
+	RoleGroupPhysicalRangedDPS = "physicalRangedDps"
+	RoleGroupMagicalRangedDPS  = "magicalRangedDps"
+	RoleGroupLimitedDPS        = "limitedDps"
+	RoleGroupCrafter           = "crafter"
+	RoleGroupGatherer          = "gatherer"
+)
+
+// 旧契約レスポンス内でのロールグループの配置パス。
+var roleGroupPaths = map[string][]string{
+	RoleGroupTank:              {"battleRoles", "tankRole"},
+	RoleGroupHealer:            {"battleRoles", "healerRole"},
+	RoleGroupMeleeDPS:          {"battleRoles", "dpsRole", "meleeDps"},
+	RoleGroupPhysicalRangedDPS: {"battleRoles", "dpsRole", "physicalRangedDps"},
+	RoleGroupMagicalRangedDPS:  {"battleRoles", "dpsRole", "magicalRangedDps"},
+	RoleGroupLimitedDPS:        {"battleRoles", "dpsRole", "limitedDps"},
+	RoleGroupCrafter:           {"crafter"},
+	RoleGroupGatherer:          {"gatherer"},
+}
+
+// FemaleNames / FemaleClassNames はFR/DE等で女性キャラクターの表示が男性形と異なる言語のみ持つ。
+type Job struct {
+	Key              string            `json:"key"`
+	RoleGroup        string            `json:"roleGroup"`
+	LodestoneClass   string            `json:"lodestoneClass"`
+	Names            map[string]string `json:"names"`
+	FemaleNames      map[string]string `json:"femaleNames,omitempty"`
+	ClassNames       map[string]string `json:"classNames,omitempty"`
+	FemaleClassNames map[string]string `json:"femaleClassNames,omitempty"`
+}
+
+type Catalog struct {
+	Version   int   `json:"version"`
+	Jobs      []Job `json:"jobs"`
+	nameIndex map[string][]string
+}
+
+// 目的: 埋め込み済みの既定カタログを返す。副作用: なし。前提: 埋め込みJSONはビルド時に検証済みである。
+func Default() *Catalog {
+	catalog, err := Parse(defaultCatalogJSON)
+	if err != nil {
+		panic(fmt.Sprintf("embedded job catalog is invalid: %v", err))
+	}
+	return catalog
+}
+
+// 目的: ファイルからジョブカタログを読み込む。副作用: ファイル読み込みを行う。前提: pathが空の場合は既定カタログを使う。
+func Load(path string) (*Catalog, error) {
+	if strings.TrimSpace(path) == "" {
+		return Default(), nil
+	}
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(body)
+}
+
+// 目的: JSONからジョブカタログを生成し整合性を検証する。副作用: なし。前提: bodyはjob_catalog.json形式である。
+func Parse(body []byte) (*Catalog, error) {
+	var catalog Catalog
+	if err := json.Unmarshal(body, &catalog); err != nil {
+		return nil, err
+	}
+	if len(catalog.Jobs) == 0 {
+		return nil, errors.New("job catalog has no jobs")
+	}
+	seenKeys := map[string]bool{}
+	for _, job := range catalog.Jobs {
+		if strings.TrimSpace(job.Key) == "" {
+			return nil, errors.New("job key is required")
+		}
+		if seenKeys[job.Key] {
+			return nil, fmt.Errorf("job key is duplicated: %s", job.Key)
+		}
+		seenKeys[job.Key] = true
+		if _, exists := roleGroupPaths[job.RoleGroup]; !exists {
+			return nil, fmt.Errorf("job %s has unknown role group: %s", job.Key, job.RoleGroup)
+		}
+		if strings.TrimSpace(job.LodestoneClass) == "" {
+			return nil, fmt.Errorf("job %s has no lodestone class", job.Key)
+		}
+		for _, locale := range supportedLocales {
+			if strings.TrimSpace(job.Names[locale]) == "" {
+				return nil, fmt.Errorf("job %s has no %s name", job.Key, locale)
+			}
+		}
+		for _, names := range []map[string]string{job.FemaleNames, job.FemaleClassNames} {
+			for locale := range names {
+				if !isSupportedLocale(locale) {
+					return nil, fmt.Errorf("job %s has female name for unsupported locale: %s", job.Key, locale)
+				}
+			}
+		}
+	}
+	catalog.nameIndex = buildNameIndex(catalog.Jobs)
+	return &catalog, nil
+}
+
+// 目的: 言語コードがLodestoneの表示言語か判定する。副作用: なし。前提: localeはカタログJSONのキーである。
+func isSupportedLocale(locale string) bool {
+	for _, supportedLocale := range supportedLocales {
+		if locale == supportedLocale {
+			return true
+		}
+	}
+	return false
+}
+
+// 目的: 表示名からジョブキー一覧への逆引き索引を構築する。副作用: なし。前提: クラス名は複数ジョブで共有され得る。女性形の表示名も同じジョブへ解決する。
+func buildNameIndex(jobs []Job) map[string][]string {
+	index := map[string][]string{}
+	addName := func(name string, jobKey string) {
+		normalizedName := normalizeName(name)
+		if normalizedName == "" {
+			return
+		}
+		for _, existingKey := range index[normalizedName] {
+			if existingKey == jobKey {
+				return
+			}
+		}
+		index[normalizedName] = append(index[normalizedName], jobKey)
+	}
+	for _, job := range jobs {
+		for _, names := range []map[string]string{job.Names, job.FemaleNames, job.ClassNames, job.FemaleClassNames} {
+			for _, name := range names {
+				addName(name, job.Key)
+			}
+		}
+	}
+	return index
+}
+
+// 目的: 名前比較用に表記揺れを吸収する。副作用: なし。前提: nameはLodestone表示文字列である。
+func normalizeName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// 目的: 表示名候補から最初に一致したジョブキー群を返す。副作用: なし。前提: 候補はジョブ名優先で並んでいる。
+func (c *Catalog) ResolveJobKeys(names []string) []string {
+	for _, name := range names {
+		if jobKeys, exists := c.nameIndex[normalizeName(name)]; exists {
+			return jobKeys
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package jobcatalog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// 目的: 既定カタログに暁月/黄金のジョブが含まれることを検証する。副作用: なし。前提: 埋め込みJSONが読み込める。
+func TestDefault_IncludesEndwalkerAndDawntrailJobs(t *testing.T) {
+	catalog := Default()
+	jobKeys := map[string]bool{}
+	for _, job := range catalog.Jobs {
+		jobKeys[job.Key] = true
+	}
+	for _, jobKey := range []string{"reaper", "sage", "viper", "pictomancer"} {
+		if !jobKeys[jobKey] {
+			t.Fatalf("want %s in default catalog", jobKey)
+		}
+	}
+}
+
+// 目的: 共有クラス名が複数ジョブへ解決され、ジョブ名は単一ジョブへ解決されることを検証する。副作用: なし。前提: 巴術士は学者と召喚士のベースクラスである。
+func TestResolveJobKeys_SharedClassResolvesToAllJobs(t *testing.T) {
+	catalog := Default()
+	if jobKeys := catalog.ResolveJobKeys([]string{"Arcaniste"}); len(jobKeys) != 2 {
+		t.Fatalf("want 2 job keys for shared class, got %v", jobKeys)
+	}
+	jobKeys := catalog.ResolveJobKeys([]string{"unknown", "  rôdeur   vipère "})
+	if len(jobKeys) != 1 || jobKeys[0] != "viper" {
+		t.Fatalf("want viper, got %v", jobKeys)
+	}
+}
+
+// 目的: FR/DEの女性キャラクター向けのジョブ名・クラス名が男性形と同じジョブへ解決されることを検証する。副作用: なし。前提: 女性形は既定カタログのfemaleNames/femaleClassNamesに定義されている。
+func TestResolveJobKeys_FemaleNamesResolveToSameJob(t *testing.T) {
+	catalog := Default()
+	testCases := map[string]string{
+		"Weißmagierin":     "whiteMage",
+		"Chevalière noire": "darkKnight",
+		"Piktomantin":      "pictomancer",
+		"Rôdeuse vipère":   "viper",
+		"Gladiatrice":      "paladin",
+	}
+	for name, wantKey := range testCases {
+		jobKeys := catalog.ResolveJobKeys([]string{name})
+		if len(jobKeys) != 1 || jobKeys[0] != wantKey {
+			t.Fatalf("want %s for %s, got %v", wantKey, name, jobKeys)
+		}
+	}
+}
+
+// 目的: 不明なロールグループや言語欠落を持つカタログを拒否することを検証する。副作用: なし。前提: 検証はParseで行われる。
+func TestParse_RejectsInvalidCatalog(t *testing.T) {
+	invalidBodies := []string{
+		`{"version":1,"jobs":[]}`,
+		`{"version":1,"jobs":[{"key":"paladin","roleGroup":"unknown","lodestoneClass":"gladiator","names":{"ja":"a","en":"b","fr":"c","de":"d"}}]}`,
+		`{"version":1,"jobs":[{"key":"paladin","roleGroup":"tankRole","lodestoneClass":"gladiator","names":{"ja":"a","en":"b"}}]}`,
+		`{"version":1,"jobs":[{"key":"paladin","roleGroup":"tankRole","lodestoneClass":"gladiator","names":{"ja":"a","en":"b","fr":"c","de":"d"},"femaleNames":{"es":"e"}}]}`,
+		`{"version":1,"jobs":[{"key":"paladin","roleGroup":"tankRole","lodestoneClass":"gladiator","names":{"ja":"a","en":"b","fr":"c","de":"d"}},{"key":"paladin","roleGroup":"tankRole","lodestoneClass":"gladiator","names":{"ja":"a","en":"b","fr":"c","de":"d"}}]}`,
+	}
+	for _, body := range invalidBodies {
+		if _, err := Parse([]byte(body)); err == nil {
+			t.Fatalf("want error for %s, got nil", body)
+		}
+	}
+}
+
+// 目的: ファイル指定時にそのカタログを読み込むことを検証する。副作用: 一時ディレクトリへファイルを書き込む。前提: ファイルは正しいカタログ形式である。
+func TestLoad_ReadsCatalogFile(t *testing.T) {
+	catalogPath := filepath.Join(t.TempDir(), "job_catalog.json")
+	body := `{"version":2,"jobs":[{"key":"paladin","roleGroup":"tankRole","lodestoneClass":"gladiator","names":{"ja":"ナイト","en":"Paladin","fr":"Paladin","de":"Paladin"}}]}`
+	if err := os.WriteFile(catalogPath, []byte(body), 0o644); err != nil {
+		t.Fatalf("failed to write catalog: %v", err)
+	}
+	catalog, err := Load(catalogPath)
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	if catalog.Version != 2 || len(catalog.Jobs) != 1 {
+		t.Fatalf("want loaded catalog, got %+v", catalog)
+	}
+}
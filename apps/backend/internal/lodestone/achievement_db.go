@@ -0,0 +1,172 @@
+package lodestone
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var achievementDBPathRegexp = regexp.MustCompile(`/lodestone/playguide/db/achievement/([0-9a-zA-Z]+)/?$`)
+
+// AchievementDBEntry はエオルゼアデータベースのアチーブメント一覧の1行。IDはURL末尾のLodestone内部IDである。
+type AchievementDBEntry struct {
+	ID    string `json:"id"`
+	URL   string `json:"url"`
+	Title string `json:"title"`
+}
+
+// AchievementDBCategoryLink は一覧ページの絞り込みリンク。KindIDはcategory2、CategoryIDはcategory3で、CategoryIDが空のリンクは種別全体を表す。
+type AchievementDBCategoryLink struct {
+	KindID     string `json:"kindId"`
+	CategoryID string `json:"categoryId,omitempty"`
+	Name       string `json:"name"`
+	URL        string `json:"url"`
+}
+
+// AchievementDBList は一覧1ページ分の結果。NextPageURLは最終ページで空となる。Categoriesはページ上の絞り込みリンクで、カテゴリ一覧URLの導出に使う。
+type AchievementDBList struct {
+	Entries     []AchievementDBEntry        `json:"entries"`
+	NextPageURL string                      `json:"nextPageUrl,omitempty"`
+	Categories  []AchievementDBCategoryLink `json:"categories,omitempty"`
+}
+
+// AchievementDBDetail はエオルゼアデータベースのアチーブメント詳細ページの内容。
+type AchievementDBDetail struct {
+	Title        string            `json:"title"`
+	Description  string            `json:"description"`
+	IconURL      string            `json:"iconUrl"`
+	Point        int               `json:"point"`
+	PatchVersion string            `json:"patchVersion,omitempty"`
+	Reward       AchievementReward `json:"reward"`
+}
+
+// 目的: アチーブメント一覧ページを取得し、詳細URLを絶対URLへ解決して返す。副作用: 外部サイトへHTTPアクセスする。前提: listURLはエオルゼアデータベースのアチーブメント一覧URLである。
+func (c *HTTPClient) FetchAchievementDBList(ctx context.Context, listURL string) (*AchievementDBList, error) {
+	doc, err := c.fetchDocument(ctx, listURL)
+	if err != nil {
+		return nil, err
+	}
+	list, err := ParseAchievementDBList(doc)
+	if err != nil {
+		return nil, err
+	}
+	baseURL, err := url.Parse(listURL)
+	if err != nil {
+		return nil, err
+	}
+	for index := range list.Entries {
+		if list.Entries[index].URL, err = resolveReference(baseURL, list.Entries[index].URL); err != nil {
+			return nil, err
+		}
+	}
+	if list.NextPageURL != "" {
+		if list.NextPageURL, err = resolveReference(baseURL, list.NextPageURL); err != nil {
+			return nil, err
+		}
+	}
+	for index := range list.Categories {
+		if list.Categories[index].URL, err = resolveReference(baseURL, list.Categories[index].URL); err != nil {
+			return nil, err
+		}
+	}
+	return list, nil
+}
+
+// 目的: エオルゼアデータベースのアチーブメント詳細ページを取得し解析する。副作用: 外部サイトへHTTPアクセスする。前提: detailURLは`/lodestone/playguide/db/achievement/{id}/`形式である。
+func (c *HTTPClient) FetchAchievementDBDetail(ctx context.Context, detailURL string) (*AchievementDBDetail, error) {
+	doc, err := c.fetchDocument(ctx, detailURL)
+	if err != nil {
+		return nil, err
+	}
+	return ParseAchievementDBDetail(doc)
+}
+
+// 目的: アチーブメント一覧ページから各行の詳細URL・ID・名称と次ページURL、絞り込みリンクを抽出する。副作用: なし。前提: URLはページ上の相対パスのまま返し、IDを取り出せない行は除外する。
+func ParseAchievementDBList(doc *goquery.Document) (*AchievementDBList, error) {
+	sel := selectors()
+	rows := sel.all(doc.Selection, "achievementDB.row")
+	if rows.Length() == 0 {
+		return nil, classifyMissingPage(sel, doc, errors.New("achievement db list is missing"))
+	}
+	list := &AchievementDBList{Entries: []AchievementDBEntry{}, NextPageURL: sel.value(doc.Selection, "achievementDB.nextPage")}
+	rows.Each(func(_ int, row *goquery.Selection) {
+		href := sel.value(row, "achievementDB.link")
+		id := AchievementDBID(href)
+		if id == "" {
+			return
+		}
+		list.Entries = append(list.Entries, AchievementDBEntry{ID: id, URL: href, Title: sel.value(row, "achievementDB.name")})
+	})
+	list.Categories = parseAchievementDBCategoryLinks(sel, doc)
+	return list, nil
+}
+
+// 目的: 一覧ページの絞り込みリンクからcategory2/category3と表示名を抽出する。副作用: なし。前提: 同じ絞り込みへの重複リンクは最初の1件のみ使い、表示名の無いリンクは除外する。
+func parseAchievementDBCategoryLinks(sel *SelectorCatalog, doc *goquery.Document) []AchievementDBCategoryLink {
+	links := []AchievementDBCategoryLink{}
+	seen := map[string]bool{}
+	sel.all(doc.Selection, "achievementDB.categoryLink").Each(func(_ int, element *goquery.Selection) {
+		href, _ := element.Attr("href")
+		name := strings.Join(strings.Fields(element.Text()), " ")
+		parsedURL, err := url.Parse(href)
+		if err != nil || name == "" {
+			return
+		}
+		query := parsedURL.Query()
+		link := AchievementDBCategoryLink{KindID: query.Get("category2"), CategoryID: query.Get("category3"), Name: name, URL: href}
+		key := link.KindID + "/" + link.CategoryID
+		if link.KindID == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		links = append(links, link)
+	})
+	return links
+}
+
+// 目的: エオルゼアデータベースのアチーブメント詳細ページから名称・説明・アイコン・ポイント・報酬を抽出する。副作用: なし。前提: 名称・説明・アイコンは必須項目である。
+func ParseAchievementDBDetail(doc *goquery.Document) (*AchievementDBDetail, error) {
+	sel := selectors()
+	title := sel.value(doc.Selection, "achievementDB.title")
+	description := sel.value(doc.Selection, "achievementDB.description")
+	iconURL := sel.value(doc.Selection, "achievementDB.icon")
+	point, _ := strconv.Atoi(sel.value(doc.Selection, "achievementDB.point"))
+	if title == "" || description == "" || iconURL == "" {
+		return nil, classifyMissingPage(sel, doc, errors.New("required achievement db fields are missing"))
+	}
+	return &AchievementDBDetail{
+		Title:        title,
+		Description:  description,
+		IconURL:      iconURL,
+		Point:        point,
+		PatchVersion: parsePatchVersion(sel.value(doc.Selection, "achievementDB.patch")),
+		Reward:       parseAchievementReward(sel, doc.Selection),
+	}, nil
+}
+
+// 目的: エオルゼアデータベースのアチーブメントURLまたはパスからIDを取り出す。副作用: なし。前提: 該当しない場合は空文字を返す。
+func AchievementDBID(rawURL string) string {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	matched := achievementDBPathRegexp.FindStringSubmatch(parsedURL.Path)
+	if len(matched) < 2 {
+		return ""
+	}
+	return matched[1]
+}
+
+// 目的: ページ上のリンクを取得元URL基準の絶対URLへ解決する。副作用: なし。前提: refは相対または絶対URLである。
+func resolveReference(baseURL *url.URL, ref string) (string, error) {
+	resolved, err := baseURL.Parse(ref)
+	if err != nil {
+		return "", err
+	}
+	return resolved.String(), nil
+}
@@ -0,0 +1,102 @@
+package lodestone
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 目的: アチーブメント一覧の各行と次ページを絶対URLで返すことを検証する。副作用: テスト用HTTPサーバを起動する。前提: 詳細リンクと次ページは相対パスで表示される。
+func TestFetchAchievementDBList_ResolvesLinks(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`
+<table class="db-table"><tbody>
+	<tr><td><a class="db-table__txt--detail_link" href="/lodestone/playguide/db/achievement/a1b2c3/">はじめての討伐</a></td></tr>
+	<tr><td><a class="db-table__txt--detail_link" href="/lodestone/playguide/db/item/zzz/">アイテム</a></td></tr>
+</tbody></table>
+<a class="btn__pager__next" href="?page=2">次へ</a>`))
+	}))
+	defer mockServer.Close()
+
+	client := NewHTTPClient(NewHTTPFetcher(mockServer.Client()), nil)
+	list, err := client.FetchAchievementDBList(context.Background(), mockServer.URL+"/lodestone/playguide/db/achievement/?category2=1")
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	if len(list.Entries) != 1 || list.Entries[0].ID != "a1b2c3" || list.Entries[0].Title != "はじめての討伐" {
+		t.Fatalf("want one achievement entry, got %+v", list.Entries)
+	}
+	if list.Entries[0].URL != mockServer.URL+"/lodestone/playguide/db/achievement/a1b2c3/" {
+		t.Fatalf("want absolute detail url, got %s", list.Entries[0].URL)
+	}
+	if list.NextPageURL != mockServer.URL+"/lodestone/playguide/db/achievement/?page=2" {
+		t.Fatalf("want absolute next page url, got %s", list.NextPageURL)
+	}
+}
+
+// 目的: 一覧ページの絞り込みリンクから種別・カテゴリのIDと表示名を絶対URLで返すことを検証する。副作用: テスト用HTTPサーバを起動する。前提: 種別リンクはcategory2のみ、カテゴリリンクはcategory3も持ち、同じ絞り込みへのリンクが重複して表示される。
+func TestFetchAchievementDBList_ParsesCategoryLinks(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`
+<ul>
+	<li><a href="/lodestone/playguide/db/achievement/?category2=1">Battle</a>
+		<ul><li><a href="/lodestone/playguide/db/achievement/?category2=1&amp;category3=4"> Dungeons </a></li></ul>
+	</li>
+	<li><a href="/lodestone/playguide/db/achievement/?category2=1">Battle</a></li>
+</ul>
+<table class="db-table"><tbody>
+	<tr><td><a class="db-table__txt--detail_link" href="/lodestone/playguide/db/achievement/a1b2c3/">To Crush Your Enemies I</a></td></tr>
+</tbody></table>`))
+	}))
+	defer mockServer.Close()
+
+	client := NewHTTPClient(NewHTTPFetcher(mockServer.Client()), nil)
+	list, err := client.FetchAchievementDBList(context.Background(), mockServer.URL+"/lodestone/playguide/db/achievement/")
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	want := []AchievementDBCategoryLink{
+		{KindID: "1", Name: "Battle", URL: mockServer.URL + "/lodestone/playguide/db/achievement/?category2=1"},
+		{KindID: "1", CategoryID: "4", Name: "Dungeons", URL: mockServer.URL + "/lodestone/playguide/db/achievement/?category2=1&category3=4"},
+	}
+	if !reflect.DeepEqual(list.Categories, want) {
+		t.Fatalf("want %+v, got %+v", want, list.Categories)
+	}
+}
+
+// 目的: アチーブメント詳細から名称・説明・アイコン・ポイント・報酬を抽出することを検証する。副作用: なし。前提: 報酬に称号とアイテムがある。
+func TestParseAchievementDBDetail_ExtractsReward(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`
+<div class="db-view__achievement__text__name">討伐の達人</div>
+<div class="db-view__achievement__help">敵を1000体討伐する。</div>
+<img class="db-view__achievement__icon__image" src="https://img.finalfantasyxiv.com/lds/pc/global/images/itemicon/aa/bb.png">
+<div class="db-view__achievement__point">10</div>
+<div class="db-view__achievement__reward">
+	<p class="db-view__achievement__reward__title">討伐の達人</p>
+	<p class="db-view__achievement__reward__item"><a href="/lodestone/playguide/db/item/abc/">記念の品</a></p>
+</div>`))
+	if err != nil {
+		t.Fatalf("failed to parse html: %v", err)
+	}
+	detail, err := ParseAchievementDBDetail(doc)
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	want := AchievementReward{Title: "討伐の達人", ItemName: "記念の品", ItemURL: "/lodestone/playguide/db/item/abc/"}
+	if detail.Title != "討伐の達人" || detail.Point != 10 || !reflect.DeepEqual(detail.Reward, want) {
+		t.Fatalf("want parsed detail with reward, got %+v", detail)
+	}
+
+	emptyDoc, err := goquery.NewDocumentFromReader(strings.NewReader(`<p>empty</p>`))
+	if err != nil {
+		t.Fatalf("failed to parse html: %v", err)
+	}
+	if _, err := ParseAchievementDBDetail(emptyDoc); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Fatalf("want missing fields error, got %v", err)
+	}
+}
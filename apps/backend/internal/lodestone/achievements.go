@@ -0,0 +1,137 @@
+package lodestone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/ff14/achievement-backend/internal/apperrors"
+	"golang.org/x/sync/errgroup"
+)
+
+type CompletedAchievementsKind struct {
+	Key          string                 `json:"key"`
+	Achievements []CompletedAchievement `json:"achievements"`
+}
+
+type CompletedAchievement struct {
+	Title         string    `json:"title"`
+	CompletedDate time.Time `json:"completedDate"`
+}
+
+type achievementKind struct {
+	Key      string
+	ID       int
+	IsSecret bool
+}
+
+// 旧`KIND`定数と同じキー/Lodestone種別IDの対応。isSecretの種別は未達成時にエラーページとなるため取得失敗を許容する。
+var achievementKinds = []achievementKind{
+	{Key: "battle", ID: 1},
+	{Key: "character", ID: 4},
+	{Key: "crafting_gathering", ID: 6},
+	{Key: "exploration", ID: 11},
+	{Key: "grand_company", ID: 12},
+	{Key: "items", ID: 5},
+	{Key: "legacy", ID: 13, IsSecret: true},
+	{Key: "pvp", ID: 2},
+	{Key: "quests", ID: 8},
+}
+
+var errAchievementListMissing = errors.New("achievement list is missing")
+
+// 目的: キャラクターページURLからアチーブメント種別ページURLを生成する。副作用: なし。前提: profileURLは正規化済みキャラクターページURLである。
+func buildAchievementKindURL(profileURL string, kindID int) string {
+	return fmt.Sprintf("%s/achievement/kind/%d/", strings.TrimSuffix(profileURL, "/"), kindID)
+}
+
+// 目的: 全アチーブメント種別ページを並行取得し達成済みアチーブメントを種別ごとに返す。副作用: 外部サイトへHTTPアクセスする。前提: 非公開設定の場合は空配列とtrueを返す。
+func (c *HTTPClient) fetchCompletedAchievementsKinds(ctx context.Context, profileURL string) ([]CompletedAchievementsKind, bool, error) {
+	results := make([]*CompletedAchievementsKind, len(achievementKinds))
+	privateFlags := make([]bool, len(achievementKinds))
+	group, groupCtx := errgroup.WithContext(ctx)
+	for index, kind := range achievementKinds {
+		group.Go(func() error {
+			achievements, err := c.fetchCompletedAchievements(groupCtx, buildAchievementKindURL(profileURL, kind.ID))
+			switch {
+			case errors.Is(err, apperrors.ErrAchievementPrivate):
+				privateFlags[index] = true
+				return nil
+			case err != nil && kind.IsSecret && !errors.Is(err, context.Canceled):
+				// isSecretの種別は未解放時にエラーページとなるため、取得失敗を許容して結果から除外する。
+				return nil
+			case err != nil:
+				return fmt.Errorf("achievement kind %s: %w", kind.Key, err)
+			}
+			results[index] = &CompletedAchievementsKind{Key: kind.Key, Achievements: achievements}
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, false, err
+	}
+	for _, isPrivate := range privateFlags {
+		if isPrivate {
+			return []CompletedAchievementsKind{}, true, nil
+		}
+	}
+	kinds := []CompletedAchievementsKind{}
+	for _, result := range results {
+		if result != nil {
+			kinds = append(kinds, *result)
+		}
+	}
+	return kinds, false, nil
+}
+
+// 目的: アチーブメント種別ページを取得し達成済みアチーブメントを返す。副作用: 外部サイトへHTTPアクセスする。前提: targetURLは`/achievement/kind/{id}/`形式である。
+func (c *HTTPClient) fetchCompletedAchievements(ctx context.Context, targetURL string) ([]CompletedAchievement, error) {
+	doc, err := c.fetchDocument(ctx, targetURL)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCompletedAchievements(doc, lodestoneRegion(targetURL))
+}
+
+// 目的: アチーブメント種別ページから達成済みアチーブメントのタイトルと達成日時を抽出する。副作用: なし。前提: 非公開時は`.parts__zero`のみが表示され、regionは日付テキストの解析に使う。
+func ParseCompletedAchievements(doc *goquery.Document, region string) ([]CompletedAchievement, error) {
+	sel := selectors()
+	achievementList := sel.first(doc.Selection, "achievement.list")
+	if achievementList.Length() == 0 {
+		if sel.exists(doc.Selection, "achievement.private") {
+			return nil, apperrors.ErrAchievementPrivate
+		}
+		return nil, classifyMissingPage(sel, doc, errAchievementListMissing)
+	}
+	completedAchievements := []CompletedAchievement{}
+	var parseErr error
+	sel.all(achievementList, "achievement.item").EachWithBreak(func(_ int, item *goquery.Selection) bool {
+		entry := sel.first(item, "achievement.entry")
+		if !sel.matches(entry, "achievement.completed") {
+			return true
+		}
+		history := sel.first(entry, "achievement.history")
+		title := sel.value(history, "achievement.title")
+		if title == "" {
+			parseErr = errors.New("completed achievement title is missing")
+			return false
+		}
+		completedDate, err := parseLodestoneTimeElement(sel.first(history, "achievement.time"), region)
+		if err != nil {
+			parseErr = fmt.Errorf("completed date of %s: %w", title, err)
+			return false
+		}
+		completedAchievements = append(completedAchievements, CompletedAchievement{
+			Title:         title,
+			CompletedDate: completedDate,
+		})
+		return true
+	})
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	return completedAchievements, nil
+}
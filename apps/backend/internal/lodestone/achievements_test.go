@@ -0,0 +1,132 @@
+package lodestone
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/ff14/achievement-backend/internal/apperrors"
+)
+
+const completedAchievementsHTML = `
+<html>
+	<body>
+		<ul class="js--achievements">
+			<li>
+				<div class="entry__achievement entry__achievement--complete">
+					<div class="entry__achievement--history">
+						<p class="entry__activity__txt">達成済み実績</p>
+						<time class="entry__activity__time"><script>document.getElementById('datetime-abc').innerHTML = ldst_strftime(1600000000, 'YMD');</script></time>
+					</div>
+				</div>
+			</li>
+			<li>
+				<div class="entry__achievement">
+					<div class="entry__achievement--history">
+						<p class="entry__activity__txt">未達成実績</p>
+					</div>
+				</div>
+			</li>
+		</ul>
+	</body>
+</html>`
+
+// 目的: 種別ページから達成済みアチーブメントのみをUTC日時付きで抽出できることを検証する。副作用: なし。前提: 未達成行は`--complete`クラスを持たない。
+func TestParseCompletedAchievements_ReturnsOnlyCompleted(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(completedAchievementsHTML))
+	if err != nil {
+		t.Fatalf("failed to parse html: %v", err)
+	}
+	achievements, err := ParseCompletedAchievements(doc, "jp")
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	if len(achievements) != 1 {
+		t.Fatalf("want 1 completed achievement, got %d", len(achievements))
+	}
+	if achievements[0].Title != "達成済み実績" {
+		t.Fatalf("want title, got %s", achievements[0].Title)
+	}
+	if !achievements[0].CompletedDate.Equal(time.Unix(1600000000, 0)) {
+		t.Fatalf("want completed date from ldst_strftime, got %v", achievements[0].CompletedDate)
+	}
+}
+
+// 目的: 非公開時の`parts__zero`表示をapperrors.ErrAchievementPrivateとして扱うことを検証する。副作用: なし。前提: 一覧要素が存在しない。
+func TestParseCompletedAchievements_PartsZeroIsPrivate(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<html><body><p class="parts__zero">非公開</p></body></html>`))
+	if err != nil {
+		t.Fatalf("failed to parse html: %v", err)
+	}
+	if _, err := ParseCompletedAchievements(doc, "jp"); !errors.Is(err, apperrors.ErrAchievementPrivate) {
+		t.Fatalf("want ErrAchievementPrivate, got %v", err)
+	}
+}
+
+// 目的: isSecret種別の取得失敗を許容し、他の種別の結果を返すことを検証する。副作用: テスト用HTTPサーバを起動する。前提: legacy(13)のみ404を返す。
+func TestFetchCompletedAchievementsKinds_ToleratesSecretKindFailure(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/achievement/kind/13/") {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(completedAchievementsHTML))
+	}))
+	defer mockServer.Close()
+
+	client := NewHTTPClient(NewHTTPFetcher(mockServer.Client()), nil)
+	kinds, isPrivate, err := client.fetchCompletedAchievementsKinds(context.Background(), mockServer.URL+"/lodestone/character/1")
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	if isPrivate {
+		t.Fatalf("want public achievements")
+	}
+	if len(kinds) != len(achievementKinds)-1 {
+		t.Fatalf("want %d kinds, got %d", len(achievementKinds)-1, len(kinds))
+	}
+	for _, kind := range kinds {
+		if kind.Key == "legacy" {
+			t.Fatalf("want legacy kind to be skipped")
+		}
+	}
+}
+
+// 目的: 非secret種別の取得失敗はエラーとして返すことを検証する。副作用: テスト用HTTPサーバを起動する。前提: battle(1)が500を返す。
+func TestFetchCompletedAchievementsKinds_NonSecretFailureReturnsError(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/achievement/kind/1/") {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(completedAchievementsHTML))
+	}))
+	defer mockServer.Close()
+
+	client := NewHTTPClient(NewHTTPFetcher(mockServer.Client()), nil)
+	if _, _, err := client.fetchCompletedAchievementsKinds(context.Background(), mockServer.URL+"/lodestone/character/1"); err == nil {
+		t.Fatalf("want error, got nil")
+	}
+}
+
+// 目的: いずれかの種別が非公開なら全体を非公開として空配列を返すことを検証する。副作用: テスト用HTTPサーバを起動する。前提: 全種別がparts__zeroを返す。
+func TestFetchCompletedAchievementsKinds_PrivateReturnsEmpty(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`<html><body><p class="parts__zero">非公開</p></body></html>`))
+	}))
+	defer mockServer.Close()
+
+	client := NewHTTPClient(NewHTTPFetcher(mockServer.Client()), nil)
+	kinds, isPrivate, err := client.fetchCompletedAchievementsKinds(context.Background(), mockServer.URL+"/lodestone/character/1")
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	if !isPrivate || len(kinds) != 0 {
+		t.Fatalf("want private with empty kinds, got %v/%d", isPrivate, len(kinds))
+	}
+}
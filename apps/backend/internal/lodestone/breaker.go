@@ -0,0 +1,151 @@
+package lodestone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ff14/achievement-backend/internal/apperrors"
+)
+
+// ErrCircuitOpen はホストのサーキットブレーカーが開いており上流へ送信しなかったことを表す。
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// BreakerConfig はホストごとのサーキットブレーカー設定。FailureThresholdが0以下の場合は遮断しない。
+type BreakerConfig struct {
+	// 連続失敗がこの回数に達すると遮断する。
+	FailureThreshold int
+	// 遮断後、復旧確認の試行（half-open）を許可するまでの時間。
+	OpenDuration time.Duration
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// BreakerFetcher はLodestoneのメンテナンス等で上流が応答しない間、タイムアウトを待たずに即座に失敗させる。
+type BreakerFetcher struct {
+	next   Fetcher
+	config BreakerConfig
+	now    func() time.Time
+	mu     sync.Mutex
+	hosts  map[string]*hostBreaker
+}
+
+type hostBreaker struct {
+	state               breakerState
+	consecutiveFailures int
+	openedAt            time.Time
+	probing             bool
+}
+
+// 目的: ホストごとのサーキットブレーカー付きFetcherを生成する。副作用: なし。前提: なし。
+func NewBreakerFetcher(next Fetcher, config BreakerConfig) *BreakerFetcher {
+	return &BreakerFetcher{next: next, config: config, now: time.Now, hosts: map[string]*hostBreaker{}}
+}
+
+// 目的: ブレーカーが閉じている、または復旧確認の試行枠がある場合のみ上流へ取得する。副作用: 上流へのHTTPアクセスとブレーカー状態の更新を行う。前提: 遮断中はErrCircuitOpenをラップしたエラーを返し、200で返されたメンテナンスページは失敗として記録する。
+func (f *BreakerFetcher) Fetch(ctx context.Context, targetURL string) (*Page, error) {
+	if f.config.FailureThreshold <= 0 {
+		return f.next.Fetch(ctx, targetURL)
+	}
+	host := breakerHost(targetURL)
+	if !f.allow(host) {
+		return nil, fmt.Errorf("%w: %s", ErrCircuitOpen, host)
+	}
+	page, err := f.next.Fetch(ctx, targetURL)
+	result := err
+	// 200で返されたメンテナンスページも上流の障害として数える。
+	if err == nil && errors.Is(classifyErrorPage(page), apperrors.ErrLodestoneMaintenance) {
+		result = apperrors.ErrLodestoneMaintenance
+	}
+	f.record(host, result, ctx.Err() != nil)
+	return page, err
+}
+
+// 目的: 現在の状態で送信してよいか判定する。副作用: 遮断時間経過後はhalf-openへ遷移し試行枠を占有する。前提: half-open中の試行は同時に1件のみ許可する。
+func (f *BreakerFetcher) allow(host string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	breaker := f.hostBreaker(host)
+	switch breaker.state {
+	case breakerOpen:
+		if f.now().Sub(breaker.openedAt) < f.config.OpenDuration {
+			return false
+		}
+		breaker.state = breakerHalfOpen
+		breaker.probing = true
+		return true
+	case breakerHalfOpen:
+		if breaker.probing {
+			return false
+		}
+		breaker.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// 目的: 取得結果をブレーカー状態へ反映する。副作用: 状態遷移を行う。前提: 呼び出し元の取消による失敗は上流の障害として数えない。
+func (f *BreakerFetcher) record(host string, err error, callerDone bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	breaker := f.hostBreaker(host)
+	if err != nil && callerDone {
+		breaker.probing = false
+		return
+	}
+	if !isUpstreamFailure(err) {
+		breaker.state = breakerClosed
+		breaker.consecutiveFailures = 0
+		breaker.probing = false
+		return
+	}
+	breaker.consecutiveFailures++
+	if breaker.state == breakerHalfOpen || breaker.consecutiveFailures >= f.config.FailureThreshold {
+		breaker.state = breakerOpen
+		breaker.openedAt = f.now()
+		breaker.probing = false
+	}
+}
+
+// 目的: ホスト名に対応するブレーカーを返し、無ければ生成する。副作用: hostsへ追加する。前提: f.muを保持している。
+func (f *BreakerFetcher) hostBreaker(host string) *hostBreaker {
+	breaker, ok := f.hosts[host]
+	if !ok {
+		breaker = &hostBreaker{}
+		f.hosts[host] = breaker
+	}
+	return breaker
+}
+
+// 目的: ブレーカーの単位となるホスト名を取り出す。副作用: なし。前提: 解析できない場合はURL全体を使う。
+func breakerHost(targetURL string) string {
+	parsedURL, err := url.Parse(targetURL)
+	if err != nil || parsedURL.Host == "" {
+		return targetURL
+	}
+	return strings.ToLower(parsedURL.Host)
+}
+
+// 目的: 上流の障害とみなす失敗か判定する。副作用: なし。前提: 404等の応答や上限超過の画像は上流が稼働しているため成功として扱う。
+func isUpstreamFailure(err error) bool {
+	if err == nil || errors.Is(err, ErrImageTooLarge) {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= 500
+	}
+	return true
+}
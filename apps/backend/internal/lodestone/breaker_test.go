@@ -0,0 +1,122 @@
+package lodestone
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// 目的: 連続失敗が閾値に達すると上流へ送信せず即座に失敗し、他ホストには影響しないことを検証する。副作用: なし。前提: 閾値は2回とする。
+func TestBreakerFetcher_OpensAfterConsecutiveFailures(t *testing.T) {
+	upstream := &countingFetcher{err: &StatusError{StatusCode: http.StatusServiceUnavailable}}
+	fetcher := NewBreakerFetcher(upstream, BreakerConfig{FailureThreshold: 2, OpenDuration: time.Minute})
+	targetURL := "https://jp.finalfantasyxiv.com/lodestone/character/1/"
+	for index := 0; index < 2; index++ {
+		if _, err := fetcher.Fetch(context.Background(), targetURL); errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("want upstream error before threshold, got %v", err)
+		}
+	}
+	if _, err := fetcher.Fetch(context.Background(), targetURL); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("want ErrCircuitOpen, got %v", err)
+	}
+	if upstream.calls[targetURL] != 2 {
+		t.Fatalf("want no upstream call while open, got %d", upstream.calls[targetURL])
+	}
+
+	upstream.err = nil
+	imageURL := "https://img.finalfantasyxiv.com/lds/pc/global/images/itemicon/aa/bb.png"
+	if _, err := fetcher.Fetch(context.Background(), imageURL); err != nil {
+		t.Fatalf("want other host unaffected, got %v", err)
+	}
+}
+
+// 目的: 遮断時間経過後は1件だけ復旧確認を許可し、成功で閉じ、失敗で再び遮断することを検証する。副作用: なし。前提: 現在時刻を差し替える。
+func TestBreakerFetcher_HalfOpenProbe(t *testing.T) {
+	upstream := &countingFetcher{err: errors.New("connection refused")}
+	fetcher := NewBreakerFetcher(upstream, BreakerConfig{FailureThreshold: 1, OpenDuration: time.Minute})
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fetcher.now = func() time.Time { return now }
+	targetURL := "https://na.finalfantasyxiv.com/lodestone/character/1/"
+
+	_, _ = fetcher.Fetch(context.Background(), targetURL)
+	now = now.Add(time.Minute)
+	if _, err := fetcher.Fetch(context.Background(), targetURL); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("want failing probe to reach upstream, got %v", err)
+	}
+	if _, err := fetcher.Fetch(context.Background(), targetURL); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("want reopened after failed probe, got %v", err)
+	}
+
+	now = now.Add(time.Minute)
+	upstream.err = nil
+	for index := 0; index < 2; index++ {
+		if _, err := fetcher.Fetch(context.Background(), targetURL); err != nil {
+			t.Fatalf("want closed after successful probe, got %v", err)
+		}
+	}
+	if upstream.calls[targetURL] != 4 {
+		t.Fatalf("want 4 upstream calls, got %d", upstream.calls[targetURL])
+	}
+}
+
+// 目的: 404は上流稼働中の応答として遮断の対象にしないことを検証する。副作用: なし。前提: 閾値は1回とする。
+func TestBreakerFetcher_IgnoresNotFound(t *testing.T) {
+	upstream := &countingFetcher{err: &StatusError{StatusCode: http.StatusNotFound}}
+	fetcher := NewBreakerFetcher(upstream, BreakerConfig{FailureThreshold: 1, OpenDuration: time.Minute})
+	targetURL := "https://jp.finalfantasyxiv.com/lodestone/playguide/db/item/abc/"
+	for index := 0; index < 3; index++ {
+		if _, err := fetcher.Fetch(context.Background(), targetURL); errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("want 404 not to open breaker, got %v", err)
+		}
+	}
+}
+
+// 目的: 200で返されたメンテナンスページを上流の障害として数え、ページ無しは数えないことを検証する。副作用: なし。前提: 閾値は1回とする。
+func TestBreakerFetcher_CountsMaintenancePageAsFailure(t *testing.T) {
+	upstream := &countingFetcher{body: `<html><body><div class="error__heading">ページが見つかりません</div></body></html>`}
+	fetcher := NewBreakerFetcher(upstream, BreakerConfig{FailureThreshold: 1, OpenDuration: time.Minute})
+	targetURL := "https://jp.finalfantasyxiv.com/lodestone/character/1/"
+	for index := 0; index < 2; index++ {
+		if _, err := fetcher.Fetch(context.Background(), targetURL); err != nil {
+			t.Fatalf("want not found page not to open breaker, got %v", err)
+		}
+	}
+
+	upstream.body = `<html><body><div class="maintenance"><h1>ただいまメンテナンス中です</h1></div></body></html>`
+	if _, err := fetcher.Fetch(context.Background(), targetURL); err != nil {
+		t.Fatalf("want maintenance page returned as is, got %v", err)
+	}
+	if _, err := fetcher.Fetch(context.Background(), targetURL); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("want circuit open after maintenance page, got %v", err)
+	}
+}
+
+// 目的: 遮断中は最後に取得できたキャッシュを期限切れと明示して返すことを検証する。副作用: なし。前提: キャッシュ→ブレーカーの順に重ねる。
+func TestCachingFetcher_ServesStaleWhileCircuitOpen(t *testing.T) {
+	upstream := &countingFetcher{}
+	breaker := NewBreakerFetcher(upstream, BreakerConfig{FailureThreshold: 1, OpenDuration: time.Minute})
+	fetcher, _ := NewCachingFetcher(breaker, CacheConfig{
+		Capacity: 8,
+		TTLs:     map[ResourceKind]time.Duration{ResourceCharacter: time.Minute},
+	})
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fetcher.now = func() time.Time { return now }
+	targetURL := "https://jp.finalfantasyxiv.com/lodestone/character/1/"
+	fresh, _ := fetcher.Fetch(context.Background(), targetURL)
+
+	now = now.Add(2 * time.Minute)
+	upstream.err = &StatusError{StatusCode: http.StatusServiceUnavailable}
+	if _, err := fetcher.Fetch(context.Background(), targetURL); err == nil {
+		t.Fatalf("want upstream error before breaker opens, got nil")
+	}
+	ctx, trace := WithCacheTrace(context.Background())
+	stale, err := fetcher.Fetch(ctx, targetURL)
+	if err != nil || string(stale.Body) != string(fresh.Body) {
+		t.Fatalf("want stale body while open, got err=%v", err)
+	}
+	if got := trace.HeaderValue(); got != `lodestone; hit; ttl=-60; detail="stale"` {
+		t.Fatalf("want stale cache status, got %q", got)
+	}
+}
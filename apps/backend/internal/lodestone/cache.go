@@ -0,0 +1,233 @@
+package lodestone
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ResourceKind はキャッシュTTLを切り替えるための取得対象の種別。
+type ResourceKind string
+
+const (
+	ResourceCharacter   ResourceKind = "character"
+	ResourceFreeCompany ResourceKind = "freecompany"
+	ResourceDatabase    ResourceKind = "database"
+	ResourceImage       ResourceKind = "image"
+)
+
+// CacheConfig はCachingFetcherの設定。TTLsに無い種別、またはTTLが0以下の種別はキャッシュしない。
+type CacheConfig struct {
+	// メモリ上に保持する最大件数。
+	Capacity int
+	// 空の場合はディスクへ永続化しない。
+	Dir  string
+	TTLs map[ResourceKind]time.Duration
+}
+
+type cacheEntry struct {
+	URL         string    `json:"url"`
+	Body        []byte    `json:"body"`
+	ContentType string    `json:"contentType"`
+	StoredAt    time.Time `json:"storedAt"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
+
+// CachingFetcher はFetcherの結果を正規化URL単位でキャッシュする。期限切れのエントリもLRUから追い出されるまでは保持する。
+type CachingFetcher struct {
+	next   Fetcher
+	ttls   map[ResourceKind]time.Duration
+	memory *lruCache
+	dir    string
+	now    func() time.Time
+}
+
+// 目的: TTL付きのメモリLRUと任意のディスク永続化を備えたFetcherを生成する。副作用: Dir指定時はディレクトリを作成する。前提: Capacityは1以上である。
+func NewCachingFetcher(next Fetcher, config CacheConfig) (*CachingFetcher, error) {
+	if config.Capacity <= 0 {
+		return nil, errors.New("cache capacity must be positive")
+	}
+	if config.Dir != "" {
+		if err := os.MkdirAll(config.Dir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+	return &CachingFetcher{
+		next:   next,
+		ttls:   config.TTLs,
+		memory: newLRUCache(config.Capacity),
+		dir:    config.Dir,
+		now:    time.Now,
+	}, nil
+}
+
+// 目的: 有効期限内のキャッシュがあれば返し、無ければ上流から取得して保存する。副作用: 上流へのHTTPアクセス、キャッシュ更新、ディスク書き込みとCacheTraceへの記録を行う。前提: 上流エラーと200で返されたメンテナンス/エラーページはキャッシュせず、ErrCircuitOpenの場合のみ期限切れエントリを返す。
+func (f *CachingFetcher) Fetch(ctx context.Context, targetURL string) (*Page, error) {
+	ttl := f.ttls[classifyResource(targetURL)]
+	if ttl <= 0 {
+		recordCacheLookup(ctx, CacheLookup{Forward: "bypass"})
+		return f.next.Fetch(ctx, targetURL)
+	}
+	key := normalizeFetchURL(targetURL)
+	now := f.now()
+	forward := "uri-miss"
+	entry, ok := f.load(key)
+	if ok {
+		if now.Before(entry.ExpiresAt) {
+			recordCacheLookup(ctx, CacheLookup{Hit: true, TTL: entry.ExpiresAt.Sub(now)})
+			return &Page{Body: entry.Body, ContentType: entry.ContentType}, nil
+		}
+		forward = "stale"
+	}
+	page, err := f.next.Fetch(ctx, targetURL)
+	if err != nil {
+		// 上流が遮断中の場合は502とせず、最後に取得できた内容を期限切れと明示して返す。
+		if ok && errors.Is(err, ErrCircuitOpen) {
+			recordCacheLookup(ctx, CacheLookup{Hit: true, Stale: true, TTL: entry.ExpiresAt.Sub(now)})
+			return &Page{Body: entry.Body, ContentType: entry.ContentType}, nil
+		}
+		return nil, err
+	}
+	// メンテナンス/エラーページは200で返ることがあるため、TTLの間返し続けないよう保存しない。
+	if classifyErrorPage(page) != nil {
+		recordCacheLookup(ctx, CacheLookup{Forward: forward})
+		return page, nil
+	}
+	f.store(&cacheEntry{
+		URL:         key,
+		Body:        page.Body,
+		ContentType: page.ContentType,
+		StoredAt:    now,
+		ExpiresAt:   now.Add(ttl),
+	})
+	recordCacheLookup(ctx, CacheLookup{Forward: forward})
+	return page, nil
+}
+
+// 目的: メモリ、無ければディスクからエントリを取り出す。副作用: ディスクから読んだエントリはメモリへ載せる。前提: keyは正規化済みURLで、ディスクの破損ファイルは未保存として扱う。
+func (f *CachingFetcher) load(key string) (*cacheEntry, bool) {
+	if entry, ok := f.memory.get(key); ok {
+		return entry, true
+	}
+	if f.dir == "" {
+		return nil, false
+	}
+	body, err := os.ReadFile(f.diskPath(key))
+	if err != nil {
+		return nil, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(body, &entry); err != nil || entry.URL != key {
+		return nil, false
+	}
+	f.memory.put(key, &entry)
+	return &entry, true
+}
+
+// 目的: エントリをメモリとディスクへ保存する。副作用: ディスクへ一時ファイル経由で書き込む。前提: ディスク書き込みの失敗はメモリキャッシュのみで継続する。
+func (f *CachingFetcher) store(entry *cacheEntry) {
+	f.memory.put(entry.URL, entry)
+	if f.dir == "" {
+		return
+	}
+	body, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	path := f.diskPath(entry.URL)
+	tempFile, err := os.CreateTemp(f.dir, ".cache-*")
+	if err != nil {
+		return
+	}
+	_, writeErr := tempFile.Write(body)
+	closeErr := tempFile.Close()
+	if writeErr != nil || closeErr != nil {
+		_ = os.Remove(tempFile.Name())
+		return
+	}
+	if err := os.Rename(tempFile.Name(), path); err != nil {
+		_ = os.Remove(tempFile.Name())
+	}
+}
+
+// 目的: URLからディスク上の保存ファイルパスを求める。副作用: なし。前提: URLはファイル名に使えないためハッシュ化する。
+func (f *CachingFetcher) diskPath(targetURL string) string {
+	sum := sha256.Sum256([]byte(targetURL))
+	return filepath.Join(f.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// 目的: URLのホストとパスからキャッシュTTLの種別を判定する。副作用: なし。前提: 画像はLodestone本体と別ホストから配信される。
+func classifyResource(targetURL string) ResourceKind {
+	parsedURL, err := url.Parse(targetURL)
+	if err != nil {
+		return ""
+	}
+	switch {
+	case strings.HasPrefix(parsedURL.Path, "/lodestone/playguide/db/"):
+		return ResourceDatabase
+	case strings.HasPrefix(parsedURL.Path, "/lodestone/freecompany/"):
+		return ResourceFreeCompany
+	case strings.HasPrefix(parsedURL.Path, "/lodestone/character/"):
+		return ResourceCharacter
+	case !strings.HasPrefix(parsedURL.Path, "/lodestone/"):
+		return ResourceImage
+	default:
+		return ""
+	}
+}
+
+// lruCache はURLをキーとした件数上限付きのLRU。
+type lruCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	items    map[string]*list.Element
+}
+
+type lruItem struct {
+	key   string
+	entry *cacheEntry
+}
+
+// 目的: 件数上限付きのLRUを生成する。副作用: なし。前提: capacityは1以上である。
+func newLRUCache(capacity int) *lruCache {
+	return &lruCache{capacity: capacity, order: list.New(), items: map[string]*list.Element{}}
+}
+
+// 目的: キーに対応するエントリを返し最近使用として扱う。副作用: LRU順序を更新する。前提: なし。
+func (c *lruCache) get(key string) (*cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*lruItem).entry, true
+}
+
+// 目的: エントリを追加または更新し上限を超えた分を古い順に追い出す。副作用: LRU内容を更新する。前提: なし。
+func (c *lruCache) put(key string, entry *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.items[key]; ok {
+		element.Value.(*lruItem).entry = entry
+		c.order.MoveToFront(element)
+		return
+	}
+	c.items[key] = c.order.PushFront(&lruItem{key: key, entry: entry})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruItem).key)
+	}
+}
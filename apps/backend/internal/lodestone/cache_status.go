@@ -0,0 +1,79 @@
+package lodestone
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CacheLookup は1回の上流取得に対するキャッシュ判定結果。Forwardは上流へ転送した理由（uri-miss/stale/bypass）。Staleは上流遮断中に期限切れエントリを返した場合にtrueとなり、TTLは負になる。
+type CacheLookup struct {
+	Hit     bool
+	Stale   bool
+	Forward string
+	TTL     time.Duration
+}
+
+// CacheTrace は1リクエスト中のキャッシュ判定を集める。キャラクター取得のように複数ページを並行取得するため排他制御する。
+type CacheTrace struct {
+	mu      sync.Mutex
+	lookups []CacheLookup
+}
+
+type cacheTraceKey struct{}
+
+// 目的: キャッシュ判定を記録するCacheTraceをコンテキストへ設定する。副作用: なし。前提: APIハンドラの開始時に1回呼ぶ。
+func WithCacheTrace(ctx context.Context) (context.Context, *CacheTrace) {
+	trace := &CacheTrace{}
+	return context.WithValue(ctx, cacheTraceKey{}, trace), trace
+}
+
+// 目的: コンテキストにCacheTraceがあれば判定結果を追加する。副作用: CacheTraceを更新する。前提: CacheTraceが無い場合は何もしない。
+func recordCacheLookup(ctx context.Context, lookup CacheLookup) {
+	trace, ok := ctx.Value(cacheTraceKey{}).(*CacheTrace)
+	if !ok {
+		return
+	}
+	trace.mu.Lock()
+	defer trace.mu.Unlock()
+	trace.lookups = append(trace.lookups, lookup)
+}
+
+// 目的: 記録した判定結果をRFC 9211形式のCache-Statusヘッダ値へ集約する。副作用: なし。前提: 全取得がキャッシュ命中した場合のみhitとし、ttlは最短の残り秒数（期限切れを返した場合は負）とする。記録が無い場合は空文字を返す。
+func (t *CacheTrace) HeaderValue() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.lookups) == 0 {
+		return ""
+	}
+	hits := 0
+	stales := 0
+	var minTTL time.Duration
+	forward := ""
+	for _, lookup := range t.lookups {
+		if lookup.Hit {
+			hits++
+			if lookup.Stale {
+				stales++
+			}
+			if hits == 1 || lookup.TTL < minTTL {
+				minTTL = lookup.TTL
+			}
+			continue
+		}
+		if forward == "" || lookup.Forward == "uri-miss" {
+			forward = lookup.Forward
+		}
+	}
+	if hits == len(t.lookups) {
+		if stales > 0 {
+			return fmt.Sprintf(`lodestone; hit; ttl=%d; detail="stale"`, int(minTTL.Seconds()))
+		}
+		return fmt.Sprintf("lodestone; hit; ttl=%d", int(minTTL.Seconds()))
+	}
+	if stales > 0 {
+		return fmt.Sprintf(`lodestone; fwd=%s; detail="%d/%d hit, %d stale"`, forward, hits, len(t.lookups), stales)
+	}
+	return fmt.Sprintf(`lodestone; fwd=%s; detail="%d/%d hit"`, forward, hits, len(t.lookups))
+}
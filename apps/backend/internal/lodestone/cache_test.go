@@ -0,0 +1,200 @@
+package lodestone
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// countingFetcher は取得回数を数えURLごとの本文を返すテスト用Fetcher。
+type countingFetcher struct {
+	mu    sync.Mutex
+	calls map[string]int
+	err   error
+	body  string
+}
+
+// 目的: 呼び出し回数を記録し`<url>#<回数>`を本文として返す。副作用: callsを更新する。前提: errが設定されている場合はエラーを返し、bodyが設定されている場合はそれを本文とする。
+func (f *countingFetcher) Fetch(_ context.Context, targetURL string) (*Page, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.calls == nil {
+		f.calls = map[string]int{}
+	}
+	f.calls[targetURL]++
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.body != "" {
+		return &Page{Body: []byte(f.body), ContentType: "text/html"}, nil
+	}
+	return &Page{Body: []byte(targetURL + "#" + strconv.Itoa(f.calls[targetURL])), ContentType: "text/html"}, nil
+}
+
+// 目的: 種別ごとのTTL内は上流を呼ばずキャッシュを返し、期限切れ後に再取得することを検証する。副作用: なし。前提: 現在時刻を差し替える。
+func TestCachingFetcher_ServesWithinTTLAndRefetchesAfterExpiry(t *testing.T) {
+	upstream := &countingFetcher{}
+	fetcher, err := NewCachingFetcher(upstream, CacheConfig{
+		Capacity: 8,
+		TTLs:     map[ResourceKind]time.Duration{ResourceCharacter: time.Minute},
+	})
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fetcher.now = func() time.Time { return now }
+	targetURL := "https://jp.finalfantasyxiv.com/lodestone/character/1/"
+
+	ctx, trace := WithCacheTrace(context.Background())
+	first, _ := fetcher.Fetch(ctx, targetURL)
+	now = now.Add(30 * time.Second)
+	second, _ := fetcher.Fetch(ctx, targetURL)
+	if upstream.calls[targetURL] != 1 || string(first.Body) != string(second.Body) {
+		t.Fatalf("want single upstream call within ttl, got %d", upstream.calls[targetURL])
+	}
+	if got := trace.HeaderValue(); got != `lodestone; fwd=uri-miss; detail="1/2 hit"` {
+		t.Fatalf("want partial hit header, got %q", got)
+	}
+
+	now = now.Add(time.Minute)
+	ctx, trace = WithCacheTrace(context.Background())
+	third, _ := fetcher.Fetch(ctx, targetURL)
+	if upstream.calls[targetURL] != 2 || string(third.Body) == string(first.Body) {
+		t.Fatalf("want refetch after expiry, got %d calls", upstream.calls[targetURL])
+	}
+	if got := trace.HeaderValue(); !strings.HasPrefix(got, "lodestone; fwd=stale") {
+		t.Fatalf("want stale forward header, got %q", got)
+	}
+
+	ctx, trace = WithCacheTrace(context.Background())
+	_, _ = fetcher.Fetch(ctx, targetURL)
+	if got := trace.HeaderValue(); got != "lodestone; hit; ttl=60" {
+		t.Fatalf("want hit header with ttl, got %q", got)
+	}
+}
+
+// 目的: TTL未設定の種別と上流エラーはキャッシュしないことを検証する。副作用: なし。前提: 画像のTTLを設定しない。
+func TestCachingFetcher_BypassesUnconfiguredKindAndErrors(t *testing.T) {
+	upstream := &countingFetcher{}
+	fetcher, _ := NewCachingFetcher(upstream, CacheConfig{
+		Capacity: 8,
+		TTLs:     map[ResourceKind]time.Duration{ResourceDatabase: time.Hour},
+	})
+	imageURL := "https://img.finalfantasyxiv.com/lds/pc/global/images/itemicon/aa/bb.png"
+	_, _ = fetcher.Fetch(context.Background(), imageURL)
+	_, _ = fetcher.Fetch(context.Background(), imageURL)
+	if upstream.calls[imageURL] != 2 {
+		t.Fatalf("want bypass for image, got %d calls", upstream.calls[imageURL])
+	}
+
+	itemURL := "https://jp.finalfantasyxiv.com/lodestone/playguide/db/item/abc/"
+	upstream.err = errors.New("boom")
+	if _, err := fetcher.Fetch(context.Background(), itemURL); err == nil {
+		t.Fatalf("want upstream error, got nil")
+	}
+	upstream.err = nil
+	if _, err := fetcher.Fetch(context.Background(), itemURL); err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	if upstream.calls[itemURL] != 2 {
+		t.Fatalf("want error not cached, got %d calls", upstream.calls[itemURL])
+	}
+}
+
+// 目的: 200で返されたメンテナンスページはキャッシュせず、次の取得で上流へ再取得することを検証する。副作用: なし。前提: セレクタは既定カタログを使う。
+func TestCachingFetcher_DoesNotCacheMaintenancePage(t *testing.T) {
+	upstream := &countingFetcher{body: `<html><body><div class="maintenance"><h1>ただいまメンテナンス中です</h1></div></body></html>`}
+	fetcher, _ := NewCachingFetcher(upstream, CacheConfig{
+		Capacity: 8,
+		TTLs:     map[ResourceKind]time.Duration{ResourceCharacter: time.Hour},
+	})
+	targetURL := "https://jp.finalfantasyxiv.com/lodestone/character/1/"
+	for index := 0; index < 2; index++ {
+		page, err := fetcher.Fetch(context.Background(), targetURL)
+		if err != nil || string(page.Body) != upstream.body {
+			t.Fatalf("want maintenance page passed through, got err=%v", err)
+		}
+	}
+	if upstream.calls[targetURL] != 2 {
+		t.Fatalf("want maintenance page not cached, got %d calls", upstream.calls[targetURL])
+	}
+
+	upstream.body = ""
+	_, _ = fetcher.Fetch(context.Background(), targetURL)
+	_, _ = fetcher.Fetch(context.Background(), targetURL)
+	if upstream.calls[targetURL] != 3 {
+		t.Fatalf("want normal page cached after maintenance, got %d calls", upstream.calls[targetURL])
+	}
+}
+
+// 目的: 件数上限を超えると最も古く使われたエントリから追い出されることを検証する。副作用: なし。前提: 容量は2件とする。
+func TestCachingFetcher_EvictsLeastRecentlyUsed(t *testing.T) {
+	upstream := &countingFetcher{}
+	fetcher, _ := NewCachingFetcher(upstream, CacheConfig{
+		Capacity: 2,
+		TTLs:     map[ResourceKind]time.Duration{ResourceCharacter: time.Hour},
+	})
+	urls := []string{
+		"https://jp.finalfantasyxiv.com/lodestone/character/1/",
+		"https://jp.finalfantasyxiv.com/lodestone/character/2/",
+		"https://jp.finalfantasyxiv.com/lodestone/character/3/",
+	}
+	ctx := context.Background()
+	_, _ = fetcher.Fetch(ctx, urls[0])
+	_, _ = fetcher.Fetch(ctx, urls[1])
+	_, _ = fetcher.Fetch(ctx, urls[0])
+	_, _ = fetcher.Fetch(ctx, urls[2])
+	_, _ = fetcher.Fetch(ctx, urls[0])
+	_, _ = fetcher.Fetch(ctx, urls[1])
+	if upstream.calls[urls[0]] != 1 || upstream.calls[urls[1]] != 2 {
+		t.Fatalf("want least recently used evicted, got %v", upstream.calls)
+	}
+}
+
+// 目的: ディスク保存したキャッシュが新しいインスタンス（再起動後）でも利用されることを検証する。副作用: 一時ディレクトリへ書き込む。前提: なし。
+func TestCachingFetcher_PersistsToDisk(t *testing.T) {
+	dir := t.TempDir()
+	config := CacheConfig{
+		Capacity: 8,
+		Dir:      dir,
+		TTLs:     map[ResourceKind]time.Duration{ResourceFreeCompany: time.Hour},
+	}
+	targetURL := "https://na.finalfantasyxiv.com/lodestone/freecompany/1/"
+	firstUpstream := &countingFetcher{}
+	firstFetcher, err := NewCachingFetcher(firstUpstream, config)
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	stored, _ := firstFetcher.Fetch(context.Background(), targetURL)
+
+	secondUpstream := &countingFetcher{}
+	secondFetcher, _ := NewCachingFetcher(secondUpstream, config)
+	restored, err := secondFetcher.Fetch(context.Background(), targetURL)
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	if secondUpstream.calls[targetURL] != 0 || string(restored.Body) != string(stored.Body) || restored.ContentType != "text/html" {
+		t.Fatalf("want restored from disk, got calls=%d body=%q", secondUpstream.calls[targetURL], restored.Body)
+	}
+}
+
+// 目的: URLからキャッシュ種別を判定できることを検証する。副作用: なし。前提: なし。
+func TestClassifyResource(t *testing.T) {
+	testCases := map[string]ResourceKind{
+		"https://jp.finalfantasyxiv.com/lodestone/character/1/class_job/":                ResourceCharacter,
+		"https://jp.finalfantasyxiv.com/lodestone/freecompany/1/member/?page=2":          ResourceFreeCompany,
+		"https://jp.finalfantasyxiv.com/lodestone/playguide/db/item/abc/":                ResourceDatabase,
+		"https://img.finalfantasyxiv.com/lds/pc/global/images/itemicon/aa/bb.png":        ResourceImage,
+		"https://img2.finalfantasyxiv.com/f/portrait_0123456789abcdef_640x873.jpg?12345": ResourceImage,
+		"https://jp.finalfantasyxiv.com/lodestone/news/":                                 "",
+	}
+	for targetURL, want := range testCases {
+		if got := classifyResource(targetURL); got != want {
+			t.Fatalf("%s: want %q, got %q", targetURL, want, got)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package lodestone
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var (
+	// jp: `星6月(6月) 17日` のように括弧内へ暦月が併記される。
+	namedayJaGregorianRegexp = regexp.MustCompile(`\((1[0-2]|[1-9])月\)\s*([0-9]{1,2})日`)
+	// jp: 暦月の併記が無い場合は `星6月 17日` / `霊6月 17日` から算出する。
+	namedayJaEorzeanRegexp = regexp.MustCompile(`([星霊])([1-6])月\s*([0-9]{1,2})日`)
+	// na/eu: `17th Sun of the 6th Astral Moon`
+	namedayEnRegexp = regexp.MustCompile(`(?i)([0-9]{1,2})(?:st|nd|rd|th)\s+Sun\s+of\s+the\s+([1-6])(?:st|nd|rd|th)\s+(Astral|Umbral)\s+Moon`)
+	// fr: `17e soleil de la 6e lune astrale` / `1er soleil de la 1re lune ombrale`
+	namedayFrRegexp = regexp.MustCompile(`(?i)([0-9]{1,2})(?:er|re|e)?\s+soleil\s+de\s+la\s+([1-6])(?:er|re|e)?\s+lune\s+(astrale|ombrale)`)
+	// de: `17. Sonne im 6. Astralmond`
+	namedayDeRegexp = regexp.MustCompile(`(?i)([0-9]{1,2})\.\s*Sonne\s+im\s+([1-6])\.\s*(Astral|Umbral)mond`)
+)
+
+type CharacterPage struct {
+	FirstName        string        `json:"firstName"`
+	LastName         string        `json:"lastName"`
+	Server           string        `json:"server"`
+	Datacenter       string        `json:"datacenter"`
+	Race             string        `json:"race"`
+	Clan             string        `json:"clan"`
+	Gender           string        `json:"gender"`
+	BirthMonth       string        `json:"birthMonth"`
+	BirthDay         string        `json:"birthDay"`
+	SelfIntroduction string        `json:"selfIntroduction"`
+	Title            string        `json:"title"`
+	Guardian         string        `json:"guardian"`
+	CityState        string        `json:"cityState"`
+	GrandCompany     *GrandCompany `json:"grandCompany,omitempty"`
+	FreeCompanyPath  string        `json:"freeCompanyPath"`
+	PortraitURL      string        `json:"portraitUrl"`
+	FaceURL          string        `json:"faceUrl"`
+}
+
+type GrandCompany struct {
+	Name        string `json:"name"`
+	Rank        string `json:"rank"`
+	RankIconURL string `json:"rankIconUrl"`
+}
+
+type characterImages struct {
+	PortraitURL string
+	FaceURL     string
+}
+
+type characterAffiliation struct {
+	Guardian     string
+	CityState    string
+	GrandCompany *GrandCompany
+}
+
+// 目的: キャラクターページからプロフィール項目を抽出する。副作用: なし。前提: 名前のみ必須項目で、欠落時はエラーを返す。種族・誕生日等は表記を解釈できない場合も空文字としプロフィール全体は返す。
+func ParseCharacterPage(doc *goquery.Document) (*CharacterPage, error) {
+	sel := selectors()
+	nameText := sel.value(doc.Selection, "character.name")
+	if nameText == "" {
+		return nil, classifyMissingPage(sel, doc, errors.New("character name is missing"))
+	}
+	firstName, lastName := splitCharacterName(nameText)
+	server, datacenter := splitServerAndDatacenter(sel.value(doc.Selection, "character.world"))
+	race, clan, gender := parseRaceClanGender(sel, doc)
+	birthMonth, birthDay := parseNameday(sel.value(doc.Selection, "character.nameday"))
+	affiliation := parseCharacterAffiliation(sel, doc)
+	images := parseCharacterImages(sel, doc)
+	return &CharacterPage{
+		FirstName:        firstName,
+		LastName:         lastName,
+		Server:           server,
+		Datacenter:       datacenter,
+		Race:             race,
+		Clan:             clan,
+		Gender:           gender,
+		BirthMonth:       birthMonth,
+		BirthDay:         birthDay,
+		SelfIntroduction: sel.value(doc.Selection, "character.selfIntroduction"),
+		Title:            parseActiveTitle(sel, doc),
+		Guardian:         affiliation.Guardian,
+		CityState:        affiliation.CityState,
+		GrandCompany:     affiliation.GrandCompany,
+		FreeCompanyPath:  sel.value(doc.Selection, "character.freeCompanyPath"),
+		PortraitURL:      images.PortraitURL,
+		FaceURL:          images.FaceURL,
+	}, nil
+}
+
+// 目的: フリーカンパニー役職の照合に使う`名 姓`形式の表示名を返す。副作用: なし。前提: LastNameが空の場合はFirstNameのみとなる。
+func (p CharacterPage) FullName() string {
+	return strings.TrimSpace(p.FirstName + " " + p.LastName)
+}
+
+// 目的: `.character-block__name`から種族・部族・性別を抽出する。副作用: なし。前提: 表示は`種族<br>部族 / ♂`形式で全リージョン共通である。解釈できない項目は空文字とする。
+func parseRaceClanGender(sel *SelectorCatalog, doc *goquery.Document) (string, string, string) {
+	nameElement := sel.first(sel.first(doc.Selection, "character.block"), "character.blockName")
+	lines := []string{}
+	nameElement.Contents().Each(func(_ int, node *goquery.Selection) {
+		if goquery.NodeName(node) != "#text" {
+			return
+		}
+		if text := strings.TrimSpace(node.Text()); text != "" {
+			lines = append(lines, text)
+		}
+	})
+	if len(lines) < 2 {
+		return "", "", ""
+	}
+	race := lines[0]
+	clan, gender, found := strings.Cut(lines[1], "/")
+	if !found {
+		return race, "", ""
+	}
+	gender = strings.TrimSpace(gender)
+	if gender != "♂" && gender != "♀" {
+		gender = ""
+	}
+	return race, strings.TrimSpace(clan), gender
+}
+
+// 目的: 各リージョンの誕生日表記から暦月と日を抽出する。副作用: なし。前提: 星（Astral）n月は2n-1月、霊（Umbral）n月は2n月に対応する。未知の表記や範囲外の値は空文字とする。
+func parseNameday(text string) (string, string) {
+	trimmed := strings.TrimSpace(text)
+	if matched := namedayJaGregorianRegexp.FindStringSubmatch(trimmed); matched != nil {
+		month, _ := strconv.Atoi(matched[1])
+		return buildNameday(month, matched[2])
+	}
+	if matched := namedayJaEorzeanRegexp.FindStringSubmatch(trimmed); matched != nil {
+		return buildEorzeanNameday(matched[2], matched[1] == "霊", matched[3])
+	}
+	for _, namedayRegexp := range []*regexp.Regexp{namedayEnRegexp, namedayFrRegexp, namedayDeRegexp} {
+		if matched := namedayRegexp.FindStringSubmatch(trimmed); matched != nil {
+			isUmbral := strings.EqualFold(matched[3], "Umbral") || strings.EqualFold(matched[3], "ombrale")
+			return buildEorzeanNameday(matched[2], isUmbral, matched[1])
+		}
+	}
+	return "", ""
+}
+
+// 目的: エオルゼア暦の月番号と星/霊区分から暦月を求める。副作用: なし。前提: moonTextは1〜6の数字文字列である。
+func buildEorzeanNameday(moonText string, isUmbral bool, dayText string) (string, string) {
+	moon, err := strconv.Atoi(moonText)
+	if err != nil {
+		return "", ""
+	}
+	month := moon*2 - 1
+	if isUmbral {
+		month = moon * 2
+	}
+	return buildNameday(month, dayText)
+}
+
+// 目的: 月日を検証し旧契約の文字列形式へ変換する。副作用: なし。前提: エオルゼア暦は1か月32日で、範囲外は空文字とする。
+func buildNameday(month int, dayText string) (string, string) {
+	day, err := strconv.Atoi(dayText)
+	if err != nil || month < 1 || month > 12 || day < 1 || day > 32 {
+		return "", ""
+	}
+	return strconv.Itoa(month), strconv.Itoa(day)
+}
+
+// 目的: 守護神・開始都市・グランドカンパニーを`.character-block`の並び順から抽出する。副作用: なし。前提: 見出しは言語ごとに異なるため、誕生日ブロックを起点に守護神/開始都市/GCの順で並ぶことを利用する。
+func parseCharacterAffiliation(sel *SelectorCatalog, doc *goquery.Document) characterAffiliation {
+	affiliation := characterAffiliation{}
+	blocks := sel.all(doc.Selection, "character.block")
+	birthIndex := -1
+	blocks.EachWithBreak(func(index int, block *goquery.Selection) bool {
+		if sel.exists(block, "character.nameday") {
+			birthIndex = index
+			return false
+		}
+		return true
+	})
+	if birthIndex < 0 {
+		return affiliation
+	}
+	affiliation.Guardian = sel.value(blocks.Eq(birthIndex), "character.blockName")
+	affiliation.CityState = sel.value(blocks.Eq(birthIndex+1), "character.blockName")
+	// GC未所属の場合は次のブロックがフリーカンパニー等となり、`.character-block__name`を持たない。
+	grandCompanyBlock := blocks.Eq(birthIndex + 2)
+	grandCompanyParts := strings.SplitN(sel.value(grandCompanyBlock, "character.blockName"), "/", 2)
+	if len(grandCompanyParts) == 2 {
+		affiliation.GrandCompany = &GrandCompany{
+			Name:        strings.TrimSpace(grandCompanyParts[0]),
+			Rank:        strings.TrimSpace(grandCompanyParts[1]),
+			RankIconURL: sel.value(grandCompanyBlock, "character.grandCompanyIcon"),
+		}
+	}
+	return affiliation
+}
+
+// 目的: キャラクター名の上下に表示される設定中の称号を抽出する。副作用: なし。前提: 称号未設定の場合は空文字を返す。
+func parseActiveTitle(sel *SelectorCatalog, doc *goquery.Document) string {
+	return sel.value(doc.Selection, "character.title")
+}
+
+// 目的: キャラクターページから全身ポートレートと顔サムネイルの画像URLを抽出する。副作用: なし。前提: 表示されていない場合は空文字を返す。
+func parseCharacterImages(sel *SelectorCatalog, doc *goquery.Document) characterImages {
+	return characterImages{
+		PortraitURL: sel.value(doc.Selection, "character.portrait"),
+		FaceURL:     sel.value(doc.Selection, "character.face"),
+	}
+}
+
+// 目的: キャラクター名文字列から姓・名を抽出する。副作用: なし。前提: textはLodestone表示名を含む。
+func splitCharacterName(text string) (string, string) {
+	parts := strings.Fields(strings.TrimSpace(text))
+	if len(parts) == 0 {
+		return "", ""
+	}
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+	return parts[0], strings.Join(parts[1:], " ")
+}
+
+// 目的: ワールド/DC表示文字列からserverとdatacenterを抽出する。副作用: なし。前提: textは`Server (DC)`形式を想定する。
+func splitServerAndDatacenter(text string) (string, string) {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return "", ""
+	}
+	matched := regexp.MustCompile(`^(.*?)\s*\((.*?)\)$`).FindStringSubmatch(trimmed)
+	if len(matched) == 3 {
+		return strings.TrimSpace(matched[1]), strings.TrimSpace(matched[2])
+	}
+	return trimmed, ""
+}
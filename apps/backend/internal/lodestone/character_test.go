@@ -0,0 +1,157 @@
+package lodestone
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 目的: 全リージョンの誕生日表記から暦月と日を抽出できることを検証する。副作用: なし。前提: 星n月は2n-1月、霊n月は2n月である。
+func TestParseNameday_SupportsAllRegions(t *testing.T) {
+	testCases := []struct {
+		text      string
+		wantMonth string
+		wantDay   string
+	}{
+		{text: "星6月(6月) 17日", wantMonth: "6", wantDay: "17"},
+		{text: "霊3月 32日", wantMonth: "6", wantDay: "32"},
+		{text: "17th Sun of the 3rd Astral Moon", wantMonth: "5", wantDay: "17"},
+		{text: "1st Sun of the 6th Umbral Moon", wantMonth: "12", wantDay: "1"},
+		{text: "1er soleil de la 1re lune astrale", wantMonth: "1", wantDay: "1"},
+		{text: "22e soleil de la 2e lune ombrale", wantMonth: "4", wantDay: "22"},
+		{text: "17. Sonne im 3. Astralmond", wantMonth: "5", wantDay: "17"},
+		{text: "8. Sonne im 4. Umbralmond", wantMonth: "8", wantDay: "8"},
+	}
+	for _, testCase := range testCases {
+		month, day := parseNameday(testCase.text)
+		if month != testCase.wantMonth || day != testCase.wantDay {
+			t.Fatalf("want %s/%s for %q, got %s/%s", testCase.wantMonth, testCase.wantDay, testCase.text, month, day)
+		}
+	}
+}
+
+// 目的: 未知の誕生日表記はエラーとせず空文字を返すことを検証する。副作用: なし。前提: 範囲外の日も未知の表記と同じ扱いとなる。
+func TestParseNameday_UnknownFormatReturnsEmpty(t *testing.T) {
+	for _, text := range []string{"", "unknown", "40th Sun of the 1st Astral Moon"} {
+		if month, day := parseNameday(text); month != "" || day != "" {
+			t.Fatalf("want empty for %q, got %s/%s", text, month, day)
+		}
+	}
+}
+
+// 目的: 種族・部族・性別を`<br>`区切りの表示から抽出できることを検証する。副作用: なし。前提: 性別は♂/♀記号で表示される。
+func TestParseRaceClanGender_ParsesCharacterBlock(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`
+<div class="character-block">
+	<p class="character-block__name">Miqo'te<br />Seeker of the Sun / ♀</p>
+</div>`))
+	if err != nil {
+		t.Fatalf("failed to parse html: %v", err)
+	}
+	race, clan, gender := parseRaceClanGender(selectors(), doc)
+	if race != "Miqo'te" || clan != "Seeker of the Sun" || gender != "♀" {
+		t.Fatalf("want Miqo'te/Seeker of the Sun/♀, got %s/%s/%s", race, clan, gender)
+	}
+}
+
+// 目的: 性別記号が無い表示は性別のみ空とし、種族と部族は返すことを検証する。副作用: なし。前提: 旧実装と同じく♂/♀以外は性別として扱わない。
+func TestParseRaceClanGender_InvalidGenderReturnsEmptyGender(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`
+<div class="character-block">
+	<p class="character-block__name">Hyur<br />Midlander / ?</p>
+</div>`))
+	if err != nil {
+		t.Fatalf("failed to parse html: %v", err)
+	}
+	race, clan, gender := parseRaceClanGender(selectors(), doc)
+	if race != "Hyur" || clan != "Midlander" || gender != "" {
+		t.Fatalf("want Hyur/Midlander/empty, got %s/%s/%s", race, clan, gender)
+	}
+}
+
+// 目的: 種族欄や誕生日の表記が未知の形式でもキャラクターページ全体は解析できることを検証する。副作用: なし。前提: 名前のみ必須項目である。
+func TestParseCharacterPage_UnknownProfileFormatsReturnEmptyValues(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`
+<p class="frame__chara__name">Test Taro</p>
+<div class="character-block">
+	<p class="character-block__name">Unknown layout</p>
+</div>
+<div class="character-block">
+	<p class="character-block__birth">someday</p>
+</div>`))
+	if err != nil {
+		t.Fatalf("failed to parse html: %v", err)
+	}
+	page, err := ParseCharacterPage(doc)
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	if page.FirstName != "Test" || page.Race != "" || page.Gender != "" || page.BirthMonth != "" || page.BirthDay != "" {
+		t.Fatalf("want name with empty profile values, got %+v", page)
+	}
+}
+
+// 目的: 誕生日ブロックを起点に守護神・開始都市・GCと階級アイコンを抽出できることを検証する。副作用: なし。前提: Lodestoneのブロック順に従うHTMLを用いる。
+func TestParseCharacterAffiliation_ParsesBlocksAfterNameday(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`
+<div class="character-block"><div class="character-block__box"><p class="character-block__name">ヒューラン<br>ミッドランダー / ♂</p></div></div>
+<div class="character-block"><div class="character-block__box">
+	<p class="character-block__birth">星6月(6月) 17日</p>
+	<p class="character-block__name">アルジク</p>
+</div></div>
+<div class="character-block"><div class="character-block__box"><p class="character-block__name">リムサ・ロミンサ</p></div></div>
+<div class="character-block"><img src="https://img.example/gc-rank.png"><div class="character-block__box"><p class="character-block__name">黒渦団/大闘佐</p></div></div>
+<div class="character-block"><div class="character__freecompany__name"><h4><a href="/lodestone/freecompany/1/">FC</a></h4></div></div>
+`))
+	if err != nil {
+		t.Fatalf("failed to parse html: %v", err)
+	}
+	affiliation := parseCharacterAffiliation(selectors(), doc)
+	if affiliation.Guardian != "アルジク" || affiliation.CityState != "リムサ・ロミンサ" {
+		t.Fatalf("want guardian/city-state, got %+v", affiliation)
+	}
+	if affiliation.GrandCompany == nil {
+		t.Fatalf("want grand company, got nil")
+	}
+	if affiliation.GrandCompany.Name != "黒渦団" || affiliation.GrandCompany.Rank != "大闘佐" || affiliation.GrandCompany.RankIconURL != "https://img.example/gc-rank.png" {
+		t.Fatalf("want grand company name/rank/icon, got %+v", affiliation.GrandCompany)
+	}
+}
+
+// 目的: GC未所属の場合にフリーカンパニーブロックをGCと誤認しないことを検証する。副作用: なし。前提: 開始都市の次がフリーカンパニーブロックである。
+func TestParseCharacterAffiliation_WithoutGrandCompany(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`
+<div class="character-block"><p class="character-block__birth">1st Sun of the 1st Astral Moon</p><p class="character-block__name">Halone, the Fury</p></div>
+<div class="character-block"><p class="character-block__name">Gridania</p></div>
+<div class="character-block"><div class="character__freecompany__name"><h4><a href="/lodestone/freecompany/1/">FC</a></h4></div></div>
+`))
+	if err != nil {
+		t.Fatalf("failed to parse html: %v", err)
+	}
+	affiliation := parseCharacterAffiliation(selectors(), doc)
+	if affiliation.Guardian != "Halone, the Fury" || affiliation.CityState != "Gridania" {
+		t.Fatalf("want guardian/city-state, got %+v", affiliation)
+	}
+	if affiliation.GrandCompany != nil {
+		t.Fatalf("want no grand company, got %+v", affiliation.GrandCompany)
+	}
+}
+
+// 目的: ポートレートと顔サムネイルの画像URLを抽出できることを検証する。副作用: なし。前提: Lodestoneと同じクラス構成のHTMLを用いる。
+func TestParseCharacterImages_ParsesPortraitAndFace(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`
+<div class="frame__chara__face"><img src="https://img2.finalfantasyxiv.com/f/abc_96x96.jpg?1700000000"></div>
+<div class="character__detail__image"><a href="https://img2.finalfantasyxiv.com/f/abc_fl0.jpg"><img src="https://img2.finalfantasyxiv.com/f/abc_640x873.jpg?1700000000"></a></div>
+`))
+	if err != nil {
+		t.Fatalf("failed to parse html: %v", err)
+	}
+	images := parseCharacterImages(selectors(), doc)
+	if images.PortraitURL != "https://img2.finalfantasyxiv.com/f/abc_640x873.jpg?1700000000" {
+		t.Fatalf("want portrait url, got %s", images.PortraitURL)
+	}
+	if images.FaceURL != "https://img2.finalfantasyxiv.com/f/abc_96x96.jpg?1700000000" {
+		t.Fatalf("want face url, got %s", images.FaceURL)
+	}
+}
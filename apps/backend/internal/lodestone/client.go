@@ -0,0 +1,163 @@
+package lodestone
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/ff14/achievement-backend/internal/jobcatalog"
+	"golang.org/x/sync/errgroup"
+)
+
+// Client はLodestoneの各ページを取得・解析する。api.Serverや各種CLIはこのインターフェースに依存する。
+type Client interface {
+	FetchCharacter(ctx context.Context, profileURL string) (*Character, error)
+	FetchCharacterCollection(ctx context.Context, profileURL string) (*Collection, error)
+	FetchFreeCompany(ctx context.Context, fcURL string, characterName string) (*FreeCompany, error)
+	FetchAchievement(ctx context.Context, achievementURL string) (*AchievementDetail, error)
+	FetchItem(ctx context.Context, itemURL string) (*ItemDetail, error)
+	FetchAchievementDBList(ctx context.Context, listURL string) (*AchievementDBList, error)
+	FetchAchievementDBDetail(ctx context.Context, detailURL string) (*AchievementDBDetail, error)
+	FetchImage(ctx context.Context, imageURL string) (*Image, error)
+}
+
+type Character struct {
+	Page                       CharacterPage               `json:"page"`
+	JobLevels                  map[string]JobLevel         `json:"jobLevels"`
+	CompletedAchievementsKinds []CompletedAchievementsKind `json:"completedAchievementsKinds"`
+	IsAchievementPrivate       bool                        `json:"isAchievementPrivate"`
+	FreeCompany                *FreeCompany                `json:"freeCompany,omitempty"`
+}
+
+// Image は内容から判定した形式と実際の縦横サイズを持つ画像。
+type Image struct {
+	Body        []byte
+	ContentType string
+	Width       int
+	Height      int
+}
+
+type HTTPClient struct {
+	fetcher Fetcher
+	catalog *jobcatalog.Catalog
+}
+
+// 目的: Fetcherとジョブカタログから既定のClient実装を生成する。副作用: なし。前提: catalogがnilの場合は埋め込みカタログを使う。
+func NewHTTPClient(fetcher Fetcher, catalog *jobcatalog.Catalog) *HTTPClient {
+	if catalog == nil {
+		catalog = jobcatalog.Default()
+	}
+	return &HTTPClient{fetcher: fetcher, catalog: catalog}
+}
+
+// 目的: HTMLページを取得しgoqueryドキュメントへ変換する。副作用: 外部サイトへHTTPアクセスする。前提: targetURLはhttp/https形式である。
+func (c *HTTPClient) fetchDocument(ctx context.Context, targetURL string) (*goquery.Document, error) {
+	page, err := c.fetcher.Fetch(ctx, targetURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch html: %w", classifyStatusError(err))
+	}
+	return goquery.NewDocumentFromReader(bytes.NewReader(page.Body))
+}
+
+// 目的: アイコンやポートレート等の画像を取得し、形式と縦横サイズを検証する。副作用: 外部サイトへHTTPアクセスする。前提: imageURLはhttp/https形式で、公開バケットへ保存されるため上流のContent-Typeではなく内容から判定した形式を返す。本文はmaxImageBytesを超えた時点で読み込みを打ち切る。
+func (c *HTTPClient) FetchImage(ctx context.Context, imageURL string) (*Image, error) {
+	page, err := c.fetcher.Fetch(withBodyLimit(ctx, maxImageBytes, ErrImageTooLarge), imageURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch binary: %w", err)
+	}
+	contentType, width, height, err := inspectImage(page.Body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid image %s: %w", imageURL, err)
+	}
+	return &Image{Body: page.Body, ContentType: contentType, Width: width, Height: height}, nil
+}
+
+// 目的: キャラクターページと関連ページを取得し解析結果をまとめて返す。副作用: 外部サイトへHTTPアクセスする。前提: profileURLは正規化済みキャラクターページURLである。
+func (c *HTTPClient) FetchCharacter(ctx context.Context, profileURL string) (*Character, error) {
+	doc, err := c.fetchDocument(ctx, profileURL)
+	if err != nil {
+		return nil, err
+	}
+	page, err := ParseCharacterPage(doc)
+	if err != nil {
+		return nil, err
+	}
+	character := &Character{Page: *page}
+
+	// ジョブ・アチーブメント・フリーカンパニーは互いに独立したページのため並行取得する。
+	// ジョブとフリーカンパニーは付加情報のため、取得に失敗しても警告を記録して既定値のままプロフィールを返す。
+	// アチーブメントは旧api/index.tsと同じく、secret・非公開以外の種別の失敗でリクエスト全体を失敗させる。
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.Go(func() error {
+		jobLevels, err := c.fetchClassJobLevels(groupCtx, profileURL)
+		if err != nil {
+			logDegradedSection(groupCtx, "class/job levels", profileURL, err)
+			jobLevels = map[string]JobLevel{}
+		}
+		character.JobLevels = jobLevels
+		return nil
+	})
+	group.Go(func() error {
+		var err error
+		character.CompletedAchievementsKinds, character.IsAchievementPrivate, err = c.fetchCompletedAchievementsKinds(groupCtx, profileURL)
+		return err
+	})
+	if page.FreeCompanyPath != "" {
+		group.Go(func() error {
+			fcURL, err := ResolveFreeCompanyURL(profileURL, page.FreeCompanyPath)
+			if err == nil {
+				character.FreeCompany, err = c.FetchFreeCompany(groupCtx, fcURL, page.FullName())
+			}
+			if err != nil {
+				logDegradedSection(groupCtx, "free company", profileURL, err)
+				character.FreeCompany = nil
+			}
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+	// 呼び出し元の取消やタイムアウトで欠けた結果は、既定値で補わずエラーとする。
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return character, nil
+}
+
+// 目的: キャラクターの付加情報の取得失敗を警告として記録する。副作用: 標準ロガーへ出力する。前提: 呼び出し元の取消による失敗は障害ではないため記録しない。
+func logDegradedSection(ctx context.Context, section string, profileURL string, err error) {
+	if ctx.Err() != nil {
+		return
+	}
+	log.Printf("warning: failed to fetch %s for %s, continuing without it: %v", section, profileURL, err)
+}
+
+// 目的: クラス/ジョブページを取得し各ジョブのレベル情報を返す。副作用: 外部サイトへHTTPアクセスする。前提: profileURLは正規化済みキャラクターページURLである。
+func (c *HTTPClient) fetchClassJobLevels(ctx context.Context, profileURL string) (map[string]JobLevel, error) {
+	doc, err := c.fetchDocument(ctx, buildClassJobURL(profileURL))
+	if err != nil {
+		return nil, err
+	}
+	return ParseClassJobLevels(doc, c.catalog)
+}
+
+// 目的: アチーブメント詳細ページを取得し解析する。副作用: 外部サイトへHTTPアクセスする。前提: URLはキャラクターアチーブメント詳細URLである。
+func (c *HTTPClient) FetchAchievement(ctx context.Context, achievementURL string) (*AchievementDetail, error) {
+	doc, err := c.fetchDocument(ctx, achievementURL)
+	if err != nil {
+		return nil, err
+	}
+	return ParseAchievementDetail(doc)
+}
+
+// 目的: アイテム詳細ページを取得し解析する。副作用: 外部サイトへHTTPアクセスする。前提: URLはLodestoneアイテム詳細URLである。
+func (c *HTTPClient) FetchItem(ctx context.Context, itemURL string) (*ItemDetail, error) {
+	doc, err := c.fetchDocument(ctx, itemURL)
+	if err != nil {
+		return nil, err
+	}
+	return ParseItemDetail(doc)
+}
@@ -0,0 +1,84 @@
+package lodestone
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// 目的: ジョブ・フリーカンパニーの取得失敗でプロフィール全体を失敗させず、各項目を既定値として返すことを検証する。副作用: テスト用HTTPサーバを起動し、警告をログ出力する。前提: クラス/ジョブとフリーカンパニーのページが500を返す。
+func TestFetchCharacter_DegradesOptionalSectionsOnFailure(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/class_job/"),
+			strings.Contains(r.URL.Path, "/freecompany/"):
+			w.WriteHeader(http.StatusInternalServerError)
+		case strings.Contains(r.URL.Path, "/achievement/kind/"):
+			_, _ = w.Write([]byte(completedAchievementsHTML))
+		default:
+			_, _ = w.Write([]byte(`
+<p class="frame__chara__name">Test Taro</p>
+<div class="character__freecompany__name"><h4><a href="/lodestone/freecompany/123/">Test Company</a></h4></div>`))
+		}
+	}))
+	defer mockServer.Close()
+
+	client := NewHTTPClient(NewHTTPFetcher(mockServer.Client()), nil)
+	character, err := client.FetchCharacter(context.Background(), mockServer.URL+"/lodestone/character/1")
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	if character.Page.FirstName != "Test" || character.JobLevels == nil || len(character.JobLevels) != 0 {
+		t.Fatalf("want profile with empty job levels, got %+v", character)
+	}
+	if character.FreeCompany != nil {
+		t.Fatalf("want free company omitted, got %+v", character.FreeCompany)
+	}
+	if len(character.CompletedAchievementsKinds) != len(achievementKinds) {
+		t.Fatalf("want %d kinds, got %d", len(achievementKinds), len(character.CompletedAchievementsKinds))
+	}
+}
+
+// 目的: secret以外のアチーブメント種別の取得失敗は、旧実装と同じくプロフィール全体のエラーとすることを検証する。副作用: テスト用HTTPサーバを起動する。前提: battle(1)のみ500を返す。
+func TestFetchCharacter_FailsWhenNonSecretAchievementKindFails(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.Contains(r.URL.Path, "/achievement/kind/1/"):
+			w.WriteHeader(http.StatusInternalServerError)
+		case strings.Contains(r.URL.Path, "/achievement/kind/"):
+			_, _ = w.Write([]byte(completedAchievementsHTML))
+		default:
+			_, _ = w.Write([]byte(`<p class="frame__chara__name">Test Taro</p>`))
+		}
+	}))
+	defer mockServer.Close()
+
+	client := NewHTTPClient(NewHTTPFetcher(mockServer.Client()), nil)
+	var statusErr *StatusError
+	if _, err := client.FetchCharacter(context.Background(), mockServer.URL+"/lodestone/character/1"); !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("want status=500 error, got %v", err)
+	}
+}
+
+// 目的: 呼び出し元の取消は既定値で補わずエラーとして返すことを検証する。副作用: テスト用HTTPサーバを起動する。前提: キャラクターページ取得後、付加情報の取得中に取消される。
+func TestFetchCharacter_ReturnsErrorWhenCallerCancels(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/lodestone/character/1") {
+			_, _ = w.Write([]byte(`<p class="frame__chara__name">Test Taro</p>`))
+			return
+		}
+		cancel()
+		<-r.Context().Done()
+	}))
+	defer mockServer.Close()
+
+	client := NewHTTPClient(NewHTTPFetcher(mockServer.Client()), nil)
+	if _, err := client.FetchCharacter(ctx, mockServer.URL+"/lodestone/character/1"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("want context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package lodestone
+
+import (
+	"context"
+	"net/url"
+	"path"
+	"strings"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// CoalescingFetcher は同一URLへの同時取得を1回の上流取得にまとめる。人気キャラクターの同時貼り付けやダブルクリックでの重複取得を防ぐ。
+type CoalescingFetcher struct {
+	next  Fetcher
+	group singleflight.Group
+}
+
+// 目的: 同時取得をまとめるFetcherを生成する。副作用: なし。前提: nextの取得はhttp.Clientのタイムアウト等で必ず終了する。
+func NewCoalescingFetcher(next Fetcher) *CoalescingFetcher {
+	return &CoalescingFetcher{next: next}
+}
+
+// 目的: 正規化URLが同じ取得を共有し、各呼び出し元は自身のコンテキストの取消で待機を打ち切れるようにする。副作用: 上流へのHTTPアクセスを行う。前提: 共有中の上流取得は特定の呼び出し元の取消では中断しない。
+func (f *CoalescingFetcher) Fetch(ctx context.Context, targetURL string) (*Page, error) {
+	// 最初の呼び出し元が取消しても他の待機者へ結果を返せるよう、上流取得は取消を切り離したコンテキストで行う。
+	upstreamCtx := context.WithoutCancel(ctx)
+	resultChan := f.group.DoChan(normalizeFetchURL(targetURL), func() (interface{}, error) {
+		return f.next.Fetch(upstreamCtx, targetURL)
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-resultChan:
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		return result.Val.(*Page), nil
+	}
+}
+
+// 目的: 同一ページを指すURLの表記揺れを吸収したキーを返す。副作用: なし。前提: スキーム・ホストの大文字小文字、フラグメント、クエリ順、Lodestoneページ末尾の`/`有無は同一視する。
+func normalizeFetchURL(targetURL string) string {
+	parsedURL, err := url.Parse(strings.TrimSpace(targetURL))
+	if err != nil {
+		return targetURL
+	}
+	parsedURL.Scheme = strings.ToLower(parsedURL.Scheme)
+	parsedURL.Host = strings.ToLower(parsedURL.Host)
+	parsedURL.Fragment = ""
+	parsedURL.RawQuery = parsedURL.Query().Encode()
+	if strings.HasPrefix(parsedURL.Path, "/lodestone/") && path.Ext(parsedURL.Path) == "" && !strings.HasSuffix(parsedURL.Path, "/") {
+		parsedURL.Path += "/"
+	}
+	return parsedURL.String()
+}
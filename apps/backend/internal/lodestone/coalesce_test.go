@@ -0,0 +1,109 @@
+package lodestone
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// blockingFetcher はreleaseが閉じられるまで応答を保留するテスト用Fetcher。
+type blockingFetcher struct {
+	calls   int32
+	started chan struct{}
+	release chan struct{}
+}
+
+// 目的: 呼び出し回数を記録し解放まで待機してから本文を返す。副作用: callsを更新しstartedへ通知する。前提: 上流のコンテキストは取消されない。
+func (f *blockingFetcher) Fetch(ctx context.Context, targetURL string) (*Page, error) {
+	atomic.AddInt32(&f.calls, 1)
+	f.started <- struct{}{}
+	select {
+	case <-f.release:
+		return &Page{Body: []byte(targetURL), ContentType: "text/html"}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// registeredContext はDoneの初回参照を通知するテスト用コンテキスト。CoalescingFetcherはDoChanへ登録した後にDoneで待機するため、通知を登録完了とみなせる。
+type registeredContext struct {
+	context.Context
+	once       sync.Once
+	registered chan<- struct{}
+}
+
+// 目的: 初回参照時に登録完了を通知し、元のコンテキストのDoneを返す。副作用: registeredへ1回送信する。前提: registeredは待機者数分のバッファを持つ。
+func (c *registeredContext) Done() <-chan struct{} {
+	c.once.Do(func() { c.registered <- struct{}{} })
+	return c.Context.Done()
+}
+
+// 目的: 表記揺れのある同一URLへの同時取得が1回の上流取得を共有し、取消した待機者のみがエラーとなることを検証する。副作用: goroutineを起動する。前提: なし。
+func TestCoalescingFetcher_SharesUpstreamAndHonorsEachContext(t *testing.T) {
+	upstream := &blockingFetcher{started: make(chan struct{}, 8), release: make(chan struct{})}
+	fetcher := NewCoalescingFetcher(upstream)
+	urls := []string{
+		"https://jp.finalfantasyxiv.com/lodestone/character/1/",
+		"https://JP.finalfantasyxiv.com/lodestone/character/1",
+		"https://jp.finalfantasyxiv.com/lodestone/character/1/#profile",
+	}
+
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancelledErr := make(chan error, 1)
+	go func() {
+		_, err := fetcher.Fetch(cancelledCtx, urls[0])
+		cancelledErr <- err
+	}()
+	<-upstream.started
+
+	registered := make(chan struct{}, len(urls))
+	var waitGroup sync.WaitGroup
+	bodies := make([]string, len(urls))
+	errs := make([]error, len(urls))
+	for index, targetURL := range urls {
+		waitGroup.Add(1)
+		go func(index int, targetURL string) {
+			defer waitGroup.Done()
+			page, err := fetcher.Fetch(&registeredContext{Context: context.Background(), registered: registered}, targetURL)
+			errs[index] = err
+			if page != nil {
+				bodies[index] = string(page.Body)
+			}
+		}(index, targetURL)
+	}
+	// 待機者が揃う前に解放しないよう、全員のDoChanへの登録を待つ。
+	for range urls {
+		<-registered
+	}
+	cancel()
+	if err := <-cancelledErr; !errors.Is(err, context.Canceled) {
+		t.Fatalf("want context.Canceled for cancelled waiter, got %v", err)
+	}
+	close(upstream.release)
+	waitGroup.Wait()
+
+	if calls := atomic.LoadInt32(&upstream.calls); calls != 1 {
+		t.Fatalf("want 1 upstream call, got %d", calls)
+	}
+	for index := range urls {
+		if errs[index] != nil || bodies[index] != urls[0] {
+			t.Fatalf("want shared result for %s, got body=%q err=%v", urls[index], bodies[index], errs[index])
+		}
+	}
+}
+
+// 目的: 表記揺れのあるURLが同じキーへ正規化されることを検証する。副作用: なし。前提: 画像URLには末尾`/`を付与しない。
+func TestNormalizeFetchURL(t *testing.T) {
+	testCases := map[string]string{
+		"HTTPS://JP.finalfantasyxiv.com/lodestone/character/1":                        "https://jp.finalfantasyxiv.com/lodestone/character/1/",
+		"https://jp.finalfantasyxiv.com/lodestone/freecompany/1/member/?page=2&b=1":   "https://jp.finalfantasyxiv.com/lodestone/freecompany/1/member/?b=1&page=2",
+		"https://img.finalfantasyxiv.com/lds/pc/global/images/itemicon/aa/bb.png?x#y": "https://img.finalfantasyxiv.com/lds/pc/global/images/itemicon/aa/bb.png?x=",
+	}
+	for input, want := range testCases {
+		if got := normalizeFetchURL(input); got != want {
+			t.Fatalf("%s: want %q, got %q", input, want, got)
+		}
+	}
+}
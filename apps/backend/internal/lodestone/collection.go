@@ -0,0 +1,93 @@
+package lodestone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	CollectionKindMount  = "mount"
+	CollectionKindMinion = "minion"
+)
+
+type Collection struct {
+	Mounts  CollectionList `json:"mounts"`
+	Minions CollectionList `json:"minions"`
+}
+
+type CollectionList struct {
+	Total int              `json:"total"`
+	Items []CollectionItem `json:"items"`
+}
+
+type CollectionItem struct {
+	Name    string `json:"name"`
+	IconURL string `json:"iconUrl"`
+}
+
+// 目的: キャラクターページURLからマウント/ミニオンタブのURLを生成する。副作用: なし。前提: kindは`mount`または`minion`である。
+func buildCollectionURL(profileURL string, kind string) string {
+	return fmt.Sprintf("%s/%s/", strings.TrimSuffix(profileURL, "/"), kind)
+}
+
+// 目的: マウントとミニオンのタブを並行取得し所持一覧を返す。副作用: 外部サイトへHTTPアクセスする。前提: profileURLは正規化済みキャラクターページURLである。
+func (c *HTTPClient) FetchCharacterCollection(ctx context.Context, profileURL string) (*Collection, error) {
+	collection := &Collection{}
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.Go(func() error {
+		var err error
+		collection.Mounts, err = c.fetchCollectionList(groupCtx, profileURL, CollectionKindMount)
+		return err
+	})
+	group.Go(func() error {
+		var err error
+		collection.Minions, err = c.fetchCollectionList(groupCtx, profileURL, CollectionKindMinion)
+		return err
+	})
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+// 目的: マウントまたはミニオンのタブを取得し所持一覧を返す。副作用: 外部サイトへHTTPアクセスする。前提: kindは`mount`または`minion`である。
+func (c *HTTPClient) fetchCollectionList(ctx context.Context, profileURL string, kind string) (CollectionList, error) {
+	doc, err := c.fetchDocument(ctx, buildCollectionURL(profileURL, kind))
+	if err != nil {
+		return CollectionList{}, err
+	}
+	return ParseCollectionList(doc, kind)
+}
+
+// 目的: マウント/ミニオンタブから名称とアイコンURLを抽出する。副作用: なし。前提: kindはmount/minionで、セレクタは`{kind}.*`キーから引き、未所持時は空一覧を返す。
+func ParseCollectionList(doc *goquery.Document, kind string) (CollectionList, error) {
+	if kind != CollectionKindMount && kind != CollectionKindMinion {
+		return CollectionList{}, errors.New("collection kind is unknown")
+	}
+	sel := selectors()
+	entries := sel.all(doc.Selection, kind+".item")
+	if entries.Length() == 0 {
+		if sel.exists(doc.Selection, kind+".empty") {
+			return CollectionList{Items: []CollectionItem{}}, nil
+		}
+		return CollectionList{}, classifyMissingPage(sel, doc, errors.New(kind+" list is missing"))
+	}
+	items := []CollectionItem{}
+	entries.Each(func(_ int, entry *goquery.Selection) {
+		items = append(items, CollectionItem{
+			Name:    sel.value(entry, kind+".name"),
+			IconURL: sel.value(entry, kind+".icon"),
+		})
+	})
+	// 合計表示はLodestone側の所持数。取得できない場合は一覧の件数を用いる。
+	total, ok := parseGroupedNumber(sel.value(doc.Selection, kind+".total"))
+	if !ok {
+		total = len(items)
+	}
+	return CollectionList{Total: total, Items: items}, nil
+}
@@ -0,0 +1,47 @@
+package lodestone
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 目的: マウント一覧から名称・アイコンと所持数を抽出できることを検証する。副作用: なし。前提: 名称要素が無い場合は画像のaltを用いる。
+func TestParseCollectionList_ParsesItemsAndTotal(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`
+<div class="mount__sort__total"><span>1,234</span></div>
+<ul class="mount__list">
+	<li class="mount__list__item"><div class="mount__list__icon"><img src="https://img.example/m1.png"></div><span class="mount__name">チョコボ</span></li>
+	<li class="mount__list__item"><div class="mount__list__icon"><img src="https://img.example/m2.png" alt="魔導アーマー"></div></li>
+</ul>`))
+	if err != nil {
+		t.Fatalf("failed to parse html: %v", err)
+	}
+	list, err := ParseCollectionList(doc, CollectionKindMount)
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	if list.Total != 1234 || len(list.Items) != 2 {
+		t.Fatalf("want total 1234 and 2 items, got %d/%d", list.Total, len(list.Items))
+	}
+	if list.Items[0] != (CollectionItem{Name: "チョコボ", IconURL: "https://img.example/m1.png"}) {
+		t.Fatalf("want first mount, got %+v", list.Items[0])
+	}
+	if list.Items[1].Name != "魔導アーマー" {
+		t.Fatalf("want alt name fallback, got %+v", list.Items[1])
+	}
+}
+
+// 目的: 未所持表示の場合は空一覧、一覧自体が無い場合はエラーとなることを検証する。副作用: なし。前提: 未所持時は`.parts__zero`が表示される。
+func TestParseCollectionList_EmptyAndMissing(t *testing.T) {
+	emptyDoc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<p class="parts__zero">該当するミニオンはありません。</p>`))
+	list, err := ParseCollectionList(emptyDoc, CollectionKindMinion)
+	if err != nil || list.Total != 0 || len(list.Items) != 0 {
+		t.Fatalf("want empty list, got %+v (%v)", list, err)
+	}
+	missingDoc, _ := goquery.NewDocumentFromReader(strings.NewReader(`<div></div>`))
+	if _, err := ParseCollectionList(missingDoc, CollectionKindMinion); err == nil {
+		t.Fatalf("want error, got nil")
+	}
+}
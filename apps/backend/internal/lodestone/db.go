@@ -0,0 +1,68 @@
+package lodestone
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/ff14/achievement-backend/internal/apperrors"
+)
+
+// パッチ表記（`パッチ7.1`、`Patch 7.1`等）からバージョン番号を取り出す。
+var patchVersionRegexp = regexp.MustCompile(`[0-9]+\.[0-9]+`)
+
+type AchievementDetail struct {
+	Title         string            `json:"title"`
+	Description   string            `json:"description"`
+	IconURL       string            `json:"iconUrl"`
+	Point         int               `json:"point"`
+	IsLatestPatch bool              `json:"isLatestPatch"`
+	PatchVersion  string            `json:"patchVersion,omitempty"`
+	Reward        AchievementReward `json:"reward"`
+}
+
+type ItemDetail struct {
+	Name     string `json:"name"`
+	ImageURL string `json:"imageUrl"`
+}
+
+// 目的: アチーブメント詳細ページから名称・説明・アイコン・ポイント・報酬を抽出する。副作用: なし。前提: 名称・説明・アイコンは必須項目で、欠落時は非公開・メンテナンス・ページ無しを判別する。
+func ParseAchievementDetail(doc *goquery.Document) (*AchievementDetail, error) {
+	sel := selectors()
+	title := sel.value(doc.Selection, "achievementDetail.title")
+	description := sel.value(doc.Selection, "achievementDetail.description")
+	iconURL := sel.value(doc.Selection, "achievementDetail.icon")
+	point, _ := strconv.Atoi(sel.value(doc.Selection, "achievementDetail.point"))
+	if title == "" || description == "" || iconURL == "" {
+		if sel.exists(doc.Selection, "achievement.private") {
+			return nil, apperrors.ErrAchievementPrivate
+		}
+		return nil, classifyMissingPage(sel, doc, errors.New("required achievement fields are missing"))
+	}
+	return &AchievementDetail{
+		Title:         title,
+		Description:   description,
+		IconURL:       iconURL,
+		Point:         point,
+		IsLatestPatch: sel.exists(doc.Selection, "achievementDetail.latestPatch"),
+		PatchVersion:  parsePatchVersion(sel.value(doc.Selection, "achievementDetail.patch")),
+		Reward:        parseAchievementReward(sel, doc.Selection),
+	}, nil
+}
+
+// 目的: パッチ表記からバージョン番号を取り出す。副作用: なし。前提: 表示言語によらず`7.1`のような数字とピリオドの並びを含み、含まない場合は空文字を返す。
+func parsePatchVersion(text string) string {
+	return patchVersionRegexp.FindString(text)
+}
+
+// 目的: アイテム詳細ページから名称と画像URLを抽出する。副作用: なし。前提: 専用要素が無い場合のOGPメタ情報へのフォールバック順はセレクタカタログで定義する。
+func ParseItemDetail(doc *goquery.Document) (*ItemDetail, error) {
+	sel := selectors()
+	name := sel.value(doc.Selection, "item.name")
+	imageURL := sel.value(doc.Selection, "item.image")
+	if name == "" || imageURL == "" {
+		return nil, classifyMissingPage(sel, doc, errors.New("required item fields are missing"))
+	}
+	return &ItemDetail{Name: name, ImageURL: imageURL}, nil
+}
@@ -0,0 +1,76 @@
+package lodestone
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 目的: アイテム専用要素が無い場合にOGPメタ情報へフォールバックすることを検証する。副作用: なし。前提: og:titleとog:imageが存在する。
+func TestParseItemDetail_FallsBackToOpenGraph(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<html><head>
+<meta property="og:title" content="テストアイテム">
+<meta property="og:image" content="https://img.finalfantasyxiv.com/item.png">
+</head><body></body></html>`))
+	if err != nil {
+		t.Fatalf("failed to parse html: %v", err)
+	}
+	item, err := ParseItemDetail(doc)
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	if item.Name != "テストアイテム" || item.ImageURL != "https://img.finalfantasyxiv.com/item.png" {
+		t.Fatalf("want og fallback, got %+v", item)
+	}
+}
+
+// 目的: パッチ表記からバージョン番号のみを取り出すことを検証する。副作用: なし。前提: 表記の前後の文言は表示言語で異なる。
+func TestParseAchievementDetail_ExtractsPatchVersion(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`
+<h3 class="db-view__achievement__text__name">テスト実績</h3>
+<p class="db-view__achievement__help">説明文</p>
+<img class="db-view__achievement__icon__image" src="https://img.finalfantasyxiv.com/icon.png">
+<span class="db-view__achievement__patch">Patch 7.05</span>`))
+	if err != nil {
+		t.Fatalf("failed to parse html: %v", err)
+	}
+	detail, err := ParseAchievementDetail(doc)
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	if detail.PatchVersion != "7.05" {
+		t.Fatalf("want 7.05, got %q", detail.PatchVersion)
+	}
+}
+
+// 目的: アチーブメント詳細の必須項目が欠けている場合はエラーとすることを検証する。副作用: なし。前提: 説明文が存在しない。
+func TestParseAchievementDetail_MissingFieldsReturnsError(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<p class="db-view__achievement__text__name">名前のみ</p>`))
+	if err != nil {
+		t.Fatalf("failed to parse html: %v", err)
+	}
+	if _, err := ParseAchievementDetail(doc); err == nil {
+		t.Fatalf("want error, got nil")
+	}
+}
+
+// 目的: 2xx以外の応答がStatusErrorとして判定でき、旧実装と同じメッセージになることを検証する。副作用: テスト用HTTPサーバを起動する。前提: サーバは404を返す。
+func TestFetchAchievement_NonSuccessStatusIsStatusError(t *testing.T) {
+	mockServer := httptest.NewServer(http.NotFoundHandler())
+	defer mockServer.Close()
+
+	client := NewHTTPClient(NewHTTPFetcher(mockServer.Client()), nil)
+	_, err := client.FetchAchievement(context.Background(), mockServer.URL+"/lodestone/character/1/achievement/detail/abc/")
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("want StatusError 404, got %v", err)
+	}
+	if err.Error() != "failed to fetch html: status=404" {
+		t.Fatalf("want legacy error message, got %s", err.Error())
+	}
+}
@@ -0,0 +1,67 @@
+package lodestone
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/ff14/achievement-backend/internal/apperrors"
+)
+
+// classifiedError は旧実装と同じメッセージのまま、共通エラーとしてもerrors.Isで判定できるよう包む。
+type classifiedError struct {
+	kind error
+	err  error
+}
+
+// 目的: 包んだ元エラーのメッセージを返す。副作用: なし。前提: なし。
+func (e *classifiedError) Error() string {
+	return e.err.Error()
+}
+
+// 目的: 共通エラーと元エラーの両方をerrors.Is/Asの探索対象にする。副作用: なし。前提: なし。
+func (e *classifiedError) Unwrap() []error {
+	return []error{e.kind, e.err}
+}
+
+// 目的: 上流のステータスコードからメンテナンス・ページ無しを判別し共通エラーで包む。副作用: なし。前提: 再試行後も503が続く場合はメンテナンスとみなし、元のStatusErrorもerrors.Asで取り出せるよう残す。
+func classifyStatusError(err error) error {
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		return err
+	}
+	switch statusErr.StatusCode {
+	case http.StatusNotFound:
+		return &classifiedError{kind: apperrors.ErrLodestoneNotFound, err: err}
+	case http.StatusServiceUnavailable:
+		return &classifiedError{kind: apperrors.ErrLodestoneMaintenance, err: err}
+	default:
+		return err
+	}
+}
+
+// 目的: 必須項目が欠落したページがLodestoneのメンテナンス/エラーページであれば共通エラーへ置き換える。副作用: なし。前提: どちらにも該当しない場合はmissingErrをそのまま返す。
+func classifyMissingPage(sel *SelectorCatalog, doc *goquery.Document, missingErr error) error {
+	switch {
+	case sel.exists(doc.Selection, "page.maintenance"):
+		return apperrors.ErrLodestoneMaintenance
+	case sel.exists(doc.Selection, "page.notFound"):
+		return apperrors.ErrLodestoneNotFound
+	default:
+		return missingErr
+	}
+}
+
+// 目的: 200で返されたHTMLがLodestoneのメンテナンス/エラーページであれば対応する共通エラーを返す。副作用: なし。前提: HTML以外（画像等）と解析できない本文は通常のページとしてnilを返す。
+func classifyErrorPage(page *Page) error {
+	if page == nil || (page.ContentType != "" && !strings.Contains(page.ContentType, "html")) {
+		return nil
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(page.Body))
+	if err != nil {
+		return nil
+	}
+	return classifyMissingPage(selectors(), doc, nil)
+}
@@ -0,0 +1,61 @@
+package lodestone
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/ff14/achievement-backend/internal/apperrors"
+)
+
+// 目的: 404/503応答がメッセージを変えずにページ無し/メンテナンスとして判定できることを検証する。副作用: テスト用HTTPサーバを起動する。前提: サーバは指定ステータスを返す。
+func TestFetchDocument_ClassifiesStatusErrors(t *testing.T) {
+	cases := map[int]error{
+		http.StatusNotFound:           apperrors.ErrLodestoneNotFound,
+		http.StatusServiceUnavailable: apperrors.ErrLodestoneMaintenance,
+	}
+	for status, want := range cases {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(status)
+		}))
+		client := NewHTTPClient(NewHTTPFetcher(mockServer.Client()), nil)
+		_, err := client.FetchCharacter(context.Background(), mockServer.URL+"/lodestone/character/1/")
+		mockServer.Close()
+		var statusErr *StatusError
+		if !errors.Is(err, want) || !errors.As(err, &statusErr) {
+			t.Fatalf("want %v wrapping StatusError for %d, got %v", want, status, err)
+		}
+	}
+}
+
+// 目的: 必須項目の無いメンテナンス/エラーページを欠落エラーではなく共通エラーとして返すことを検証する。副作用: なし。前提: セレクタは既定カタログを使う。
+func TestParseCharacterPage_DetectsErrorPages(t *testing.T) {
+	cases := map[string]error{
+		`<div class="maintenance"><h1>ただいまメンテナンス中です</h1></div>`: apperrors.ErrLodestoneMaintenance,
+		`<div class="error__heading">ページが見つかりません</div>`:         apperrors.ErrLodestoneNotFound,
+	}
+	for body, want := range cases {
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader("<html><body>" + body + "</body></html>"))
+		if err != nil {
+			t.Fatalf("failed to parse html: %v", err)
+		}
+		if _, err := ParseCharacterPage(doc); !errors.Is(err, want) {
+			t.Fatalf("want %v, got %v", want, err)
+		}
+	}
+}
+
+// 目的: 非公開キャラクターのアチーブメント詳細ページをErrAchievementPrivateとして返すことを検証する。副作用: なし。前提: 詳細要素の代わりに`.parts__zero`が表示される。
+func TestParseAchievementDetail_PrivateCharacter(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<html><body><p class="parts__zero">非公開</p></body></html>`))
+	if err != nil {
+		t.Fatalf("failed to parse html: %v", err)
+	}
+	if _, err := ParseAchievementDetail(doc); !errors.Is(err, apperrors.ErrAchievementPrivate) {
+		t.Fatalf("want ErrAchievementPrivate, got %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package lodestone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 上流の応答本文の上限。Lodestoneのページや画像より十分大きく、異常な応答でメモリを使い切らない値とする。
+const maxResponseBytes = 16 << 20
+
+// ErrResponseTooLarge は応答本文が上限を超えたことを表す。
+var ErrResponseTooLarge = errors.New("response body is too large")
+
+// bodyLimit は取得種別ごとの本文上限。画像取得では保存上限で読み込みを打ち切るため、コンテキストで上限と超過時のエラーを渡す。
+type bodyLimit struct {
+	maxBytes int
+	err      error
+}
+
+type bodyLimitKey struct{}
+
+// 目的: 本文の読み込み上限をコンテキストへ設定する。副作用: なし。前提: 未設定の場合はmaxResponseBytesとErrResponseTooLargeを使う。
+func withBodyLimit(ctx context.Context, maxBytes int, err error) context.Context {
+	return context.WithValue(ctx, bodyLimitKey{}, bodyLimit{maxBytes: maxBytes, err: err})
+}
+
+// 目的: コンテキストに設定された本文上限を取り出す。副作用: なし。前提: 未設定の場合は既定の上限を返す。
+func bodyLimitFrom(ctx context.Context) bodyLimit {
+	if limit, ok := ctx.Value(bodyLimitKey{}).(bodyLimit); ok {
+		return limit
+	}
+	return bodyLimit{maxBytes: maxResponseBytes, err: ErrResponseTooLarge}
+}
+
+// Fetcher はLodestoneへのHTTP取得を抽象化する。キャッシュや流量制御はこのインターフェースを包んで差し込む。
+type Fetcher interface {
+	Fetch(ctx context.Context, targetURL string) (*Page, error)
+}
+
+type Page struct {
+	Body        []byte
+	ContentType string
+}
+
+// StatusError は2xx以外の応答を表す。呼び出し側はerrors.Asでステータスを判定する。RetryAfterは応答にRetry-Afterがあった場合のみ設定される。
+type StatusError struct {
+	StatusCode int
+	RetryAfter time.Duration
+}
+
+// 目的: 旧実装と同じ`status=<code>`形式のメッセージを返す。副作用: なし。前提: StatusCodeは2xx以外である。
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("status=%d", e.StatusCode)
+}
+
+type HTTPFetcher struct {
+	httpClient *http.Client
+}
+
+// 目的: net/httpクライアントで取得するFetcherを生成する。副作用: なし。前提: httpClientにはタイムアウトが設定済みである。
+func NewHTTPFetcher(httpClient *http.Client) *HTTPFetcher {
+	return &HTTPFetcher{httpClient: httpClient}
+}
+
+// 目的: 外部URLから本文とContent-Typeを取得する。副作用: HTTPリクエストを送信する。前提: targetURLはhttp/https形式で、本文が上限（既定はmaxResponseBytes、withBodyLimitで変更）を超える場合は上限+1バイトで読み込みを打ち切りエラーを返す。
+func (f *HTTPFetcher) Fetch(ctx context.Context, targetURL string) (*Page, error) {
+	parsedURL, err := url.ParseRequestURI(targetURL)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, parsedURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, &StatusError{
+			StatusCode: resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+	}
+	limit := bodyLimitFrom(ctx)
+	body, err := io.ReadAll(io.LimitReader(resp.Body, int64(limit.maxBytes)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > limit.maxBytes {
+		return nil, fmt.Errorf("%w: over %d bytes", limit.err, limit.maxBytes)
+	}
+	return &Page{Body: body, ContentType: resp.Header.Get("Content-Type")}, nil
+}
+
+// 目的: Retry-Afterヘッダの秒数またはHTTP日付を待機時間へ変換する。副作用: なし。前提: 未指定・解析不能・過去日時の場合は0を返す。
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(trimmed); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	retryAt, err := http.ParseTime(trimmed)
+	if err != nil || !retryAt.After(now) {
+		return 0
+	}
+	return retryAt.Sub(now)
+}
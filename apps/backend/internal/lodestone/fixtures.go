@@ -0,0 +1,76 @@
+package lodestone
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FixturePage はcmd/lodestone-fixtureが保存するページ名と取得元URLの組。Nameはゴールデンテストで使うパーサの選択にも使う。
+type FixturePage struct {
+	Name string
+	URL  string
+}
+
+// 目的: リージョンとキャラクターIDからキャラクターページURLを生成する。副作用: なし。前提: regionは`jp/na/eu/fr/de`のいずれかである。
+func BuildCharacterProfileURL(region string, characterID string) string {
+	return fmt.Sprintf("https://%s.finalfantasyxiv.com/lodestone/character/%s/", region, characterID)
+}
+
+// 目的: キャラクター配下で解析対象となるページ一覧を返す。副作用: なし。前提: profileURLは正規化済みキャラクターページURLである。
+func CharacterFixturePages(profileURL string) []FixturePage {
+	return []FixturePage{
+		{Name: "character", URL: profileURL},
+		{Name: "class_job", URL: buildClassJobURL(profileURL)},
+		{Name: "achievement_kind", URL: buildAchievementKindURL(profileURL, achievementKinds[0].ID)},
+		{Name: CollectionKindMount, URL: buildCollectionURL(profileURL, CollectionKindMount)},
+		{Name: CollectionKindMinion, URL: buildCollectionURL(profileURL, CollectionKindMinion)},
+	}
+}
+
+// 目的: 女性キャラクターのページ一覧を返す。副作用: なし。前提: FR/DEのジョブ名・部族名は性別で表記が変わるため、キャラクターとクラス/ジョブページを別名で記録する。
+func FemaleCharacterFixturePages(profileURL string) []FixturePage {
+	return []FixturePage{
+		{Name: "character_female", URL: profileURL},
+		{Name: "class_job_female", URL: buildClassJobURL(profileURL)},
+	}
+}
+
+// 目的: フリーカンパニートップとメンバー一覧1ページ目を返す。副作用: なし。前提: fcURLは`/`終端のフリーカンパニーページURLである。
+func FreeCompanyFixturePages(fcURL string) []FixturePage {
+	return []FixturePage{
+		{Name: "freecompany", URL: fcURL},
+		{Name: "freecompany_member", URL: fcURL + "member/?page=1"},
+	}
+}
+
+// 目的: キャラクターのアチーブメント詳細ページを返す。副作用: なし。前提: achievementIDはLodestoneのアチーブメントIDである。
+func AchievementDetailFixturePage(profileURL string, achievementID string) FixturePage {
+	return FixturePage{
+		Name: "achievement_detail",
+		URL:  fmt.Sprintf("%s/achievement/detail/%s/", strings.TrimSuffix(profileURL, "/"), achievementID),
+	}
+}
+
+// 目的: エオルゼアデータベースのアイテム詳細ページを返す。副作用: なし。前提: itemIDはLodestoneのアイテムIDである。
+func ItemDetailFixturePage(region string, itemID string) FixturePage {
+	return FixturePage{
+		Name: "db_item",
+		URL:  fmt.Sprintf("https://%s.finalfantasyxiv.com/lodestone/playguide/db/item/%s/", region, itemID),
+	}
+}
+
+// 目的: エオルゼアデータベースのアチーブメント一覧1ページ目を返す。副作用: なし。前提: 絞り込み無しの一覧を記録する。
+func AchievementDBListFixturePage(region string) FixturePage {
+	return FixturePage{
+		Name: "db_achievement_list",
+		URL:  fmt.Sprintf("https://%s.finalfantasyxiv.com/lodestone/playguide/db/achievement/", region),
+	}
+}
+
+// 目的: エオルゼアデータベースのアチーブメント詳細ページを返す。副作用: なし。前提: achievementIDは一覧の詳細リンク末尾のIDである。
+func AchievementDBDetailFixturePage(region string, achievementID string) FixturePage {
+	return FixturePage{
+		Name: "db_achievement",
+		URL:  fmt.Sprintf("https://%s.finalfantasyxiv.com/lodestone/playguide/db/achievement/%s/", region, achievementID),
+	}
+}
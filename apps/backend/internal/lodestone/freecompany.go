@@ -0,0 +1,147 @@
+package lodestone
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/sync/errgroup"
+)
+
+// Lodestoneのフリーカンパニーメンバー一覧は1ページ50名表示。Lodestone側の表示数が変わった場合はここも変更する。
+const freeCompanyMembersPerPage = 50
+
+var freeCompanyMemberCountRegexp = regexp.MustCompile(`([0-9][0-9,.]*)`)
+
+type FreeCompany struct {
+	FCName               string   `json:"fcName"`
+	FCTag                string   `json:"fcTag"`
+	FCMemberCount        int      `json:"fcMemberCount"`
+	FCCrestBaseImageURLs []string `json:"fcCrestBaseImageUrls"`
+	FCHouseState         string   `json:"fcHouseState,omitempty"`
+	PositionBaseImageURL string   `json:"positionBaseImageUrl"`
+	PositionName         string   `json:"positionName"`
+}
+
+// FreeCompanyPosition はメンバー一覧上の役職。キーは旧APIのfreecompanyInfoの役職項目に合わせる。
+type FreeCompanyPosition struct {
+	ImageURL string `json:"positionBaseImageUrl"`
+	Name     string `json:"positionName"`
+}
+
+// 目的: キャラクターページから所属フリーカンパニーのパスを取り出す。副作用: なし。前提: 未所属の場合は空文字を返す。
+func ParseFreeCompanyPath(doc *goquery.Document) string {
+	return selectors().value(doc.Selection, "character.freeCompanyPath")
+}
+
+// 目的: キャラクターページURLを基準にフリーカンパニーページの絶対URLを解決する。副作用: なし。前提: fcPathは`/lodestone/freecompany/{id}/`形式である。
+func ResolveFreeCompanyURL(profileURL string, fcPath string) (string, error) {
+	baseURL, err := url.Parse(profileURL)
+	if err != nil {
+		return "", err
+	}
+	fcURL, err := baseURL.Parse(fcPath)
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasSuffix(fcURL.Path, "/") {
+		fcURL.Path += "/"
+	}
+	return fcURL.String(), nil
+}
+
+// 目的: フリーカンパニー情報とキャラクターの役職を取得する。副作用: 外部サイトへHTTPアクセスする。前提: fcURLはフリーカンパニートップページURLで、characterNameは`名 姓`形式である。
+func (c *HTTPClient) FetchFreeCompany(ctx context.Context, fcURL string, characterName string) (*FreeCompany, error) {
+	doc, err := c.fetchDocument(ctx, fcURL)
+	if err != nil {
+		return nil, err
+	}
+	info, err := ParseFreeCompanyInfo(doc)
+	if err != nil {
+		return nil, err
+	}
+	position, err := c.findFreeCompanyPosition(ctx, fcURL, info.FCMemberCount, characterName)
+	if err != nil {
+		return nil, err
+	}
+	info.PositionBaseImageURL = position.ImageURL
+	info.PositionName = position.Name
+	return info, nil
+}
+
+// 目的: メンバー一覧の全ページを並行取得しキャラクターの役職を探す。副作用: 外部サイトへHTTPアクセスする。前提: ページ数はメンバー数から算出し、どのページにも見つからない場合は空の役職を返す。
+func (c *HTTPClient) findFreeCompanyPosition(ctx context.Context, fcURL string, memberCount int, characterName string) (FreeCompanyPosition, error) {
+	totalPages := (memberCount + freeCompanyMembersPerPage - 1) / freeCompanyMembersPerPage
+	if totalPages < 1 {
+		totalPages = 1
+	}
+	positions := make([]*FreeCompanyPosition, totalPages)
+	group, groupCtx := errgroup.WithContext(ctx)
+	for page := 1; page <= totalPages; page++ {
+		group.Go(func() error {
+			doc, err := c.fetchDocument(groupCtx, fmt.Sprintf("%smember/?page=%d", fcURL, page))
+			if err != nil {
+				return err
+			}
+			positions[page-1] = ParseFreeCompanyPosition(doc, characterName)
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return FreeCompanyPosition{}, err
+	}
+	for _, position := range positions {
+		if position != nil {
+			return *position, nil
+		}
+	}
+	// 加入直後等でメンバー一覧へ未反映の場合も、プロフィール全体を失敗させず役職のみ空で返す。
+	return FreeCompanyPosition{}, nil
+}
+
+// 目的: フリーカンパニーページから名称・タグ・クレスト・メンバー数・ハウス情報を抽出する。副作用: なし。前提: メンバー数は4番目の`.freecompany__text`に表示される。
+func ParseFreeCompanyInfo(doc *goquery.Document) (*FreeCompany, error) {
+	sel := selectors()
+	crestImageURLs := sel.values(sel.first(doc.Selection, "freeCompany.crest"), "freeCompany.crestImage")
+	name := sel.value(doc.Selection, "freeCompany.name")
+	if name == "" {
+		return nil, classifyMissingPage(sel, doc, errors.New("free company name is missing"))
+	}
+	tag := sel.value(doc.Selection, "freeCompany.tag")
+	memberCountText := sel.all(doc.Selection, "freeCompany.text").Eq(3).Text()
+	matched := freeCompanyMemberCountRegexp.FindString(memberCountText)
+	memberCount, err := strconv.Atoi(strings.NewReplacer(",", "", ".", "").Replace(matched))
+	if err != nil {
+		return nil, errors.New("free company member count is missing")
+	}
+	return &FreeCompany{
+		FCName:               name,
+		FCTag:                tag,
+		FCMemberCount:        memberCount,
+		FCCrestBaseImageURLs: crestImageURLs,
+		FCHouseState:         sel.value(doc.Selection, "freeCompany.estate"),
+	}, nil
+}
+
+// 目的: メンバー一覧ページから指定キャラクターの役職名と役職アイコンを探す。副作用: なし。前提: 見つからない場合はnilを返す。
+func ParseFreeCompanyPosition(doc *goquery.Document, characterName string) *FreeCompanyPosition {
+	sel := selectors()
+	var position *FreeCompanyPosition
+	sel.all(doc.Selection, "freeCompany.member").EachWithBreak(func(_ int, member *goquery.Selection) bool {
+		if sel.value(member, "freeCompany.memberName") != characterName {
+			return true
+		}
+		info := sel.first(member, "freeCompany.memberPosition")
+		position = &FreeCompanyPosition{
+			ImageURL: sel.value(info, "freeCompany.positionIcon"),
+			Name:     sel.value(info, "freeCompany.positionName"),
+		}
+		return false
+	})
+	return position
+}
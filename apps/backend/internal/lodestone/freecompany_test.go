@@ -0,0 +1,91 @@
+package lodestone
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+const freeCompanyTopHTML = `
+<html>
+	<body>
+		<div class="entry__freecompany__crest__image">
+			<img src="https://img.finalfantasyxiv.com/crest/base.png" />
+			<img src="https://img.finalfantasyxiv.com/crest/frame.png" />
+		</div>
+		<p class="freecompany__text__name">Test Company</p>
+		<p class="freecompany__text freecompany__text__tag">«TEST»</p>
+		<p class="freecompany__text">Immortal Flames</p>
+		<p class="freecompany__text">2020/01/01</p>
+		<p class="freecompany__text">72名</p>
+		<p class="freecompany__estate__text">Mist, Plot 1</p>
+	</body>
+</html>`
+
+// 目的: メンバー数から全ページを並行取得し、2ページ目にいるキャラクターの役職を返すことを検証する。副作用: テスト用HTTPサーバを起動する。前提: 72名は2ページに分かれる。
+func TestFetchFreeCompanyInfo_FindsPositionOnLaterPage(t *testing.T) {
+	var memberPageRequests int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/member/") {
+			_, _ = w.Write([]byte(freeCompanyTopHTML))
+			return
+		}
+		atomic.AddInt32(&memberPageRequests, 1)
+		memberName := "Other Member"
+		if r.URL.Query().Get("page") == "2" {
+			memberName = "Test Taro"
+		}
+		_, _ = w.Write([]byte(`
+<div class="entry__freecompany__center">
+	<p class="entry__name">` + memberName + `</p>
+	<ul class="entry__freecompany__info"><li><img src="https://img.finalfantasyxiv.com/rank.png" /><span>Officer</span></li></ul>
+</div>`))
+	}))
+	defer mockServer.Close()
+
+	client := NewHTTPClient(NewHTTPFetcher(mockServer.Client()), nil)
+	fcURL, err := ResolveFreeCompanyURL(mockServer.URL+"/lodestone/character/1", "/lodestone/freecompany/123")
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	info, err := client.FetchFreeCompany(context.Background(), fcURL, "Test Taro")
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	if info.FCName != "Test Company" || info.FCTag != "«TEST»" || info.FCMemberCount != 72 {
+		t.Fatalf("want fc name/tag/count, got %+v", info)
+	}
+	if len(info.FCCrestBaseImageURLs) != 2 || info.FCHouseState != "Mist, Plot 1" {
+		t.Fatalf("want crest layers and estate, got %+v", info)
+	}
+	if info.PositionName != "Officer" || info.PositionBaseImageURL != "https://img.finalfantasyxiv.com/rank.png" {
+		t.Fatalf("want position from page 2, got %+v", info)
+	}
+	if atomic.LoadInt32(&memberPageRequests) != 2 {
+		t.Fatalf("want 2 member page requests, got %d", memberPageRequests)
+	}
+}
+
+// 目的: メンバー一覧に存在しない場合もエラーとせず、役職を空にしてフリーカンパニー情報を返すことを検証する。副作用: テスト用HTTPサーバを起動する。前提: メンバーページにキャラクターが含まれない。
+func TestFetchFreeCompanyInfo_MemberNotFoundOmitsPosition(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/member/") {
+			_, _ = w.Write([]byte(freeCompanyTopHTML))
+			return
+		}
+		_, _ = w.Write([]byte(`<div class="entry__freecompany__center"><p class="entry__name">Other Member</p></div>`))
+	}))
+	defer mockServer.Close()
+
+	client := NewHTTPClient(NewHTTPFetcher(mockServer.Client()), nil)
+	info, err := client.FetchFreeCompany(context.Background(), mockServer.URL+"/lodestone/freecompany/123/", "Test Taro")
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	if info.FCName != "Test Company" || info.PositionName != "" || info.PositionBaseImageURL != "" {
+		t.Fatalf("want free company without position, got %+v", info)
+	}
+}
@@ -0,0 +1,158 @@
+package lodestone
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/ff14/achievement-backend/internal/jobcatalog"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite testdata/golden from testdata/fixtures")
+
+const (
+	fixtureRootDir = "testdata/fixtures"
+	goldenRootDir  = "testdata/golden"
+)
+
+// goldenFixture はフィクスチャ1件の解析に必要な情報。characterNameは同一リージョンのcharacter.htmlから求める。
+type goldenFixture struct {
+	region        string
+	doc           *goquery.Document
+	characterName string
+}
+
+// ゴールデン出力。解析エラーもマークアップ変化の兆候として差分対象にする。
+type goldenOutput struct {
+	Result any    `json:"result,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// フィクスチャ名（cmd/lodestone-fixtureのFixturePage.Name）ごとの解析関数。
+var goldenParsers = map[string]func(fixture goldenFixture) (any, error){
+	"character": func(fixture goldenFixture) (any, error) {
+		return ParseCharacterPage(fixture.doc)
+	},
+	"class_job": func(fixture goldenFixture) (any, error) {
+		return ParseClassJobLevels(fixture.doc, jobcatalog.Default())
+	},
+	"character_female": func(fixture goldenFixture) (any, error) {
+		return ParseCharacterPage(fixture.doc)
+	},
+	"class_job_female": func(fixture goldenFixture) (any, error) {
+		return ParseClassJobLevels(fixture.doc, jobcatalog.Default())
+	},
+	"achievement_kind": func(fixture goldenFixture) (any, error) {
+		return ParseCompletedAchievements(fixture.doc, fixture.region)
+	},
+	CollectionKindMount: func(fixture goldenFixture) (any, error) {
+		return ParseCollectionList(fixture.doc, CollectionKindMount)
+	},
+	CollectionKindMinion: func(fixture goldenFixture) (any, error) {
+		return ParseCollectionList(fixture.doc, CollectionKindMinion)
+	},
+	"freecompany": func(fixture goldenFixture) (any, error) {
+		return ParseFreeCompanyInfo(fixture.doc)
+	},
+	"freecompany_member": func(fixture goldenFixture) (any, error) {
+		return ParseFreeCompanyPosition(fixture.doc, fixture.characterName), nil
+	},
+	"achievement_detail": func(fixture goldenFixture) (any, error) {
+		return ParseAchievementDetail(fixture.doc)
+	},
+	"db_item": func(fixture goldenFixture) (any, error) {
+		return ParseItemDetail(fixture.doc)
+	},
+	"db_achievement_list": func(fixture goldenFixture) (any, error) {
+		return ParseAchievementDBList(fixture.doc)
+	},
+	"db_achievement": func(fixture goldenFixture) (any, error) {
+		return ParseAchievementDBDetail(fixture.doc)
+	},
+}
+
+// 目的: 保存済みHTMLを読み込みgoqueryドキュメントへ変換する。副作用: ファイル読み込みを行う。前提: pathはフィクスチャHTMLである。
+func loadFixtureDocument(t *testing.T, path string) *goquery.Document {
+	t.Helper()
+	body, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to parse fixture: %v", err)
+	}
+	return doc
+}
+
+// 目的: 全リージョンの全フィクスチャを対応するパーサで解析し、ゴールデンJSONとの差分を検証する。副作用: `-update`指定時はゴールデンを書き換える。前提: フィクスチャはcmd/lodestone-fixtureで記録したオフラインHTMLである。
+func TestParsers_MatchGoldenFixtures(t *testing.T) {
+	regionEntries, err := os.ReadDir(fixtureRootDir)
+	if err != nil {
+		t.Fatalf("failed to read fixtures: %v", err)
+	}
+	for _, regionEntry := range regionEntries {
+		if !regionEntry.IsDir() {
+			continue
+		}
+		region := regionEntry.Name()
+		t.Run(region, func(t *testing.T) {
+			fixturePaths, err := filepath.Glob(filepath.Join(fixtureRootDir, region, "*.html"))
+			if err != nil || len(fixturePaths) == 0 {
+				t.Fatalf("want fixtures for %s, got none (err=%v)", region, err)
+			}
+			characterName := ""
+			characterPath := filepath.Join(fixtureRootDir, region, "character.html")
+			if _, err := os.Stat(characterPath); err == nil {
+				if page, err := ParseCharacterPage(loadFixtureDocument(t, characterPath)); err == nil {
+					characterName = page.FullName()
+				}
+			}
+			for _, fixturePath := range fixturePaths {
+				name := strings.TrimSuffix(filepath.Base(fixturePath), ".html")
+				t.Run(name, func(t *testing.T) {
+					parse, exists := goldenParsers[name]
+					if !exists {
+						t.Fatalf("want parser for fixture %s, got none", name)
+					}
+					result, err := parse(goldenFixture{
+						region:        region,
+						doc:           loadFixtureDocument(t, fixturePath),
+						characterName: characterName,
+					})
+					output := goldenOutput{Result: result}
+					if err != nil {
+						output = goldenOutput{Error: err.Error()}
+					}
+					got, err := json.MarshalIndent(output, "", "  ")
+					if err != nil {
+						t.Fatalf("failed to marshal result: %v", err)
+					}
+					got = append(got, '\n')
+					goldenPath := filepath.Join(goldenRootDir, region, name+".json")
+					if *updateGolden {
+						if err := os.MkdirAll(filepath.Dir(goldenPath), 0o755); err != nil {
+							t.Fatalf("failed to create golden dir: %v", err)
+						}
+						if err := os.WriteFile(goldenPath, got, 0o644); err != nil {
+							t.Fatalf("failed to write golden: %v", err)
+						}
+						return
+					}
+					want, err := os.ReadFile(goldenPath)
+					if err != nil {
+						t.Fatalf("failed to read golden (run with -update to create): %v", err)
+					}
+					if !bytes.Equal(got, want) {
+						t.Fatalf("want golden %s, got:\n%s", goldenPath, got)
+					}
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package lodestone
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"net/http"
+)
+
+const (
+	// アイコン・ポートレートとして保存を許可する画像の最大サイズ。
+	maxImageBytes = 5 << 20
+	// 縦横いずれかがこれを超える画像は展開時のメモリ消費を避けるため拒否する。
+	maxImageDimension = 4096
+)
+
+var (
+	// ErrImageTooLarge は画像のバイト数または縦横サイズが上限を超えたことを表す。
+	ErrImageTooLarge = errors.New("image is too large")
+	// ErrUnsupportedImage は本文がPNG/JPEG/GIF/WebPとして解釈できないことを表す。
+	ErrUnsupportedImage = errors.New("unsupported image type")
+)
+
+// 目的: 画像本文の形式を内容から判定し縦横サイズを読み取る。副作用: なし。前提: 上流のContent-Typeは信用せず、PNG/JPEG/GIF/WebPのみを許可する。
+func inspectImage(body []byte) (string, int, int, error) {
+	if len(body) > maxImageBytes {
+		return "", 0, 0, fmt.Errorf("%w: %d bytes", ErrImageTooLarge, len(body))
+	}
+	contentType := http.DetectContentType(body)
+	var width, height int
+	switch contentType {
+	case "image/png", "image/jpeg", "image/gif":
+		config, _, err := image.DecodeConfig(bytes.NewReader(body))
+		if err != nil {
+			return "", 0, 0, fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+		}
+		width, height = config.Width, config.Height
+	case "image/webp":
+		var err error
+		if width, height, err = decodeWebPSize(body); err != nil {
+			return "", 0, 0, err
+		}
+	default:
+		return "", 0, 0, fmt.Errorf("%w: %s", ErrUnsupportedImage, contentType)
+	}
+	if width <= 0 || height <= 0 {
+		return "", 0, 0, fmt.Errorf("%w: empty dimensions", ErrUnsupportedImage)
+	}
+	if width > maxImageDimension || height > maxImageDimension {
+		return "", 0, 0, fmt.Errorf("%w: %dx%d", ErrImageTooLarge, width, height)
+	}
+	return contentType, width, height, nil
+}
+
+// 目的: WebPのRIFFヘッダから縦横サイズを読み取る。副作用: なし。前提: 標準ライブラリにWebPデコーダが無いため、VP8/VP8L/VP8Xの先頭チャンクのみを解釈する。
+func decodeWebPSize(body []byte) (int, int, error) {
+	if len(body) < 30 || string(body[0:4]) != "RIFF" || string(body[8:12]) != "WEBP" {
+		return 0, 0, fmt.Errorf("%w: broken webp header", ErrUnsupportedImage)
+	}
+	chunk := body[20:]
+	switch string(body[12:16]) {
+	case "VP8 ":
+		if chunk[3] != 0x9d || chunk[4] != 0x01 || chunk[5] != 0x2a {
+			return 0, 0, fmt.Errorf("%w: broken vp8 frame", ErrUnsupportedImage)
+		}
+		return int(binary.LittleEndian.Uint16(chunk[6:8]) & 0x3fff), int(binary.LittleEndian.Uint16(chunk[8:10]) & 0x3fff), nil
+	case "VP8L":
+		if chunk[0] != 0x2f {
+			return 0, 0, fmt.Errorf("%w: broken vp8l signature", ErrUnsupportedImage)
+		}
+		bits := binary.LittleEndian.Uint32(chunk[1:5])
+		return int(bits&0x3fff) + 1, int((bits>>14)&0x3fff) + 1, nil
+	case "VP8X":
+		width := int(chunk[4]) | int(chunk[5])<<8 | int(chunk[6])<<16
+		height := int(chunk[7]) | int(chunk[8])<<8 | int(chunk[9])<<16
+		return width + 1, height + 1, nil
+	default:
+		return 0, 0, fmt.Errorf("%w: unknown webp chunk", ErrUnsupportedImage)
+	}
+}
@@ -0,0 +1,147 @@
+package lodestone
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// 目的: 上流のContent-Typeではなく内容から形式を判定し、実際の縦横サイズを返すことを検証する。副作用: テスト用HTTPサーバを起動する。前提: サーバはGIFをimage/pngと偽って返す。
+func TestFetchImage_SniffsTypeAndDimensions(t *testing.T) {
+	var body bytes.Buffer
+	if err := gif.Encode(&body, image.NewPaletted(image.Rect(0, 0, 40, 30), color.Palette{color.Black, color.White}), nil); err != nil {
+		t.Fatalf("failed to encode gif: %v", err)
+	}
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write(body.Bytes())
+	}))
+	defer mockServer.Close()
+
+	client := NewHTTPClient(NewHTTPFetcher(mockServer.Client()), nil)
+	fetched, err := client.FetchImage(context.Background(), mockServer.URL+"/icon.png")
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	if fetched.ContentType != "image/gif" || fetched.Width != 40 || fetched.Height != 30 {
+		t.Fatalf("want image/gif 40x30, got %s %dx%d", fetched.ContentType, fetched.Width, fetched.Height)
+	}
+}
+
+// 目的: 画像以外の本文や上限を超える画像を拒否することを検証する。副作用: なし。前提: 縦横の上限はmaxImageDimensionである。
+func TestInspectImage_RejectsUnsafePayloads(t *testing.T) {
+	var oversized bytes.Buffer
+	if err := png.Encode(&oversized, image.NewGray(image.Rect(0, 0, maxImageDimension+1, 1))); err != nil {
+		t.Fatalf("failed to encode png: %v", err)
+	}
+	cases := []struct {
+		name string
+		body []byte
+		want error
+	}{
+		{name: "html", body: []byte("<html><script>alert(1)</script></html>"), want: ErrUnsupportedImage},
+		{name: "svg", body: []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`), want: ErrUnsupportedImage},
+		{name: "broken png", body: []byte("\x89PNG\r\n\x1a\nbroken"), want: ErrUnsupportedImage},
+		{name: "dimension", body: oversized.Bytes(), want: ErrImageTooLarge},
+		{name: "bytes", body: append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, maxImageBytes)...), want: ErrImageTooLarge},
+	}
+	for _, tc := range cases {
+		if _, _, _, err := inspectImage(tc.body); !errors.Is(err, tc.want) {
+			t.Fatalf("%s: want %v, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+// 目的: WebPの各形式のヘッダから縦横サイズを読み取れることを検証する。副作用: なし。前提: ヘッダ部分のみを組み立てる。
+func TestInspectImage_WebPDimensions(t *testing.T) {
+	riff := func(fourCC string, chunk []byte) []byte {
+		body := append([]byte("RIFF\x00\x00\x00\x00WEBP"+fourCC+"\x00\x00\x00\x00"), chunk...)
+		return append(body, make([]byte, 16)...)
+	}
+	// VP8L: 幅-1と高さ-1を14bitずつ格納する。
+	bits := uint32(128-1) | uint32(64-1)<<14
+	lossless := riff("VP8L", []byte{0x2f, byte(bits), byte(bits >> 8), byte(bits >> 16), byte(bits >> 24)})
+	lossy := riff("VP8 ", []byte{0, 0, 0, 0x9d, 0x01, 0x2a, 80, 0, 40, 0})
+	extended := riff("VP8X", []byte{0, 0, 0, 0, 255, 0, 0, 127, 0, 0})
+	cases := map[string]struct {
+		body          []byte
+		width, height int
+	}{
+		"VP8L": {body: lossless, width: 128, height: 64},
+		"VP8 ": {body: lossy, width: 80, height: 40},
+		"VP8X": {body: extended, width: 256, height: 128},
+	}
+	for name, tc := range cases {
+		contentType, width, height, err := inspectImage(tc.body)
+		if err != nil || contentType != "image/webp" || width != tc.width || height != tc.height {
+			t.Fatalf("%s: want image/webp %dx%d, got %s %dx%d (%v)", name, tc.width, tc.height, contentType, width, height, err)
+		}
+	}
+}
+
+// 目的: 応答本文が上限を超える場合に読み込みを打ち切りErrResponseTooLargeを返すことを検証する。副作用: テスト用HTTPサーバを起動する。前提: サーバは上限+1バイトを返す。
+func TestHTTPFetcher_RejectsOversizedBody(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(make([]byte, maxResponseBytes+1))
+	}))
+	defer mockServer.Close()
+
+	if _, err := NewHTTPFetcher(mockServer.Client()).Fetch(context.Background(), mockServer.URL); !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("want ErrResponseTooLarge, got %v", err)
+	}
+}
+
+// 目的: 画像取得では16MiBではなく画像の上限で読み込みを打ち切りErrImageTooLargeを返すことを検証する。副作用: テスト用HTTPサーバを起動する。前提: サーバは上限を大きく超える本文を返し、書き込めたバイト数を記録する。
+func TestFetchImage_StopsReadingAtImageLimit(t *testing.T) {
+	var written atomic.Int64
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		chunk := make([]byte, 64<<10)
+		for i := 0; i < maxResponseBytes/len(chunk); i++ {
+			n, err := w.Write(chunk)
+			written.Add(int64(n))
+			if err != nil {
+				return
+			}
+		}
+	}))
+	defer mockServer.Close()
+
+	client := NewHTTPClient(NewHTTPFetcher(mockServer.Client()), nil)
+	if _, err := client.FetchImage(context.Background(), mockServer.URL+"/huge.png"); !errors.Is(err, ErrImageTooLarge) {
+		t.Fatalf("want ErrImageTooLarge, got %v", err)
+	}
+	mockServer.CloseClientConnections()
+	if got := written.Load(); got >= maxResponseBytes {
+		t.Fatalf("want read to stop before %d bytes, server wrote %d", maxResponseBytes, got)
+	}
+}
+
+// 目的: 許可ホスト外へのリダイレクトを拒否し、同一ホスト内とLodestone配下へのhttpsリダイレクトは許可することを検証する。副作用: なし。前提: viaの先頭が元のリクエストである。
+func TestCheckRedirect_AllowsOnlyLodestoneHosts(t *testing.T) {
+	origin, _ := http.NewRequest(http.MethodGet, "https://img.finalfantasyxiv.com/lds/a.png", nil)
+	cases := map[string]bool{
+		"https://img.finalfantasyxiv.com/lds/b.png":     true,
+		"https://lds-img.finalfantasyxiv.com/itemicon/": true,
+		"http://jp.finalfantasyxiv.com/lodestone/":      false,
+		"https://evil.example.com/a.png":                false,
+		"https://finalfantasyxiv.com.evil.example/":     false,
+	}
+	for target, allowed := range cases {
+		req, _ := http.NewRequest(http.MethodGet, target, nil)
+		err := CheckRedirect(req, []*http.Request{origin})
+		if allowed && err != nil {
+			t.Fatalf("want %s allowed, got %v", target, err)
+		}
+		if !allowed && !errors.Is(err, ErrRedirectNotAllowed) {
+			t.Fatalf("want %s rejected, got %v", target, err)
+		}
+	}
+}
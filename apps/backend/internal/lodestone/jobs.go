@@ -0,0 +1,105 @@
+package lodestone
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/ff14/achievement-backend/internal/jobcatalog"
+)
+
+// HasExp はLodestone上で経験値が表示されている場合のみtrueとなる（未解放・カンスト時はfalse）。
+type JobLevel struct {
+	Level      int  `json:"level"`
+	CurrentExp int  `json:"currentExp"`
+	NextExp    int  `json:"nextExp"`
+	HasExp     bool `json:"hasExp"`
+}
+
+// 目的: キャラクターページURLからクラス/ジョブページURLを生成する。副作用: なし。前提: profileURLは正規化済みキャラクターページURLである。
+func buildClassJobURL(profileURL string) string {
+	return strings.TrimSuffix(profileURL, "/") + "/class_job/"
+}
+
+// 目的: クラス/ジョブページから各ジョブのレベルと経験値を抽出する。副作用: なし。前提: docはLodestoneのclass_jobページで、catalogは照合用のジョブカタログである。
+func ParseClassJobLevels(doc *goquery.Document, catalog *jobcatalog.Catalog) (map[string]JobLevel, error) {
+	sel := selectors()
+	entries := sel.all(doc.Selection, "classJob.entry")
+	if entries.Length() == 0 {
+		return nil, classifyMissingPage(sel, doc, errors.New("class job list is missing"))
+	}
+	levels := map[string]JobLevel{}
+	entries.Each(func(_ int, entry *goquery.Selection) {
+		jobKeys := catalog.ResolveJobKeys(collectJobEntryNames(sel, entry))
+		if len(jobKeys) == 0 {
+			return
+		}
+		level := JobLevel{Level: parseJobLevelText(sel.value(entry, "classJob.level"))}
+		level.CurrentExp, level.NextExp, level.HasExp = parseJobExpText(sel.value(entry, "classJob.exp"))
+		for _, jobKey := range jobKeys {
+			levels[jobKey] = level
+		}
+	})
+	return levels, nil
+}
+
+// 目的: ジョブ行から照合候補となる表示名を優先順に集める。副作用: なし。前提: tooltipは`ジョブ / クラス`形式を想定する。
+func collectJobEntryNames(sel *SelectorCatalog, entry *goquery.Selection) []string {
+	names := []string{}
+	tooltip := sel.value(entry, "classJob.tooltip")
+	for _, part := range strings.Split(tooltip, "/") {
+		if trimmed := strings.TrimSpace(part); trimmed != "" {
+			names = append(names, trimmed)
+		}
+	}
+	if nameText := sel.value(entry, "classJob.name"); nameText != "" {
+		names = append(names, nameText)
+	}
+	return names
+}
+
+// 目的: レベル表示文字列を数値へ変換する。副作用: なし。前提: 未解放ジョブは`-`表示で0扱いとする。
+func parseJobLevelText(text string) int {
+	level, err := strconv.Atoi(strings.TrimSpace(text))
+	if err != nil {
+		return 0
+	}
+	return level
+}
+
+// 目的: `現在 / 次` 形式の経験値表示を数値へ変換する。副作用: なし。前提: 未表示やカンスト時は`-- / --`となり取得不可として扱う。
+func parseJobExpText(text string) (int, int, bool) {
+	parts := strings.Split(text, "/")
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+	currentExp, currentOK := parseGroupedNumber(parts[0])
+	nextExp, nextOK := parseGroupedNumber(parts[1])
+	if !currentOK || !nextOK {
+		return 0, 0, false
+	}
+	return currentExp, nextExp, true
+}
+
+// 目的: 桁区切り（`,` `.` 空白）付きの数値表示を整数へ変換する。副作用: なし。前提: textは非負整数の表示である。
+func parseGroupedNumber(text string) (int, bool) {
+	digits := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		if r == ',' || r == '.' || unicode.IsSpace(r) {
+			return -1
+		}
+		return 'x'
+	}, strings.TrimSpace(text))
+	if digits == "" {
+		return 0, false
+	}
+	value, err := strconv.Atoi(digits)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
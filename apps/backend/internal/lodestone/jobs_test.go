@@ -0,0 +1,114 @@
+package lodestone
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/ff14/achievement-backend/internal/jobcatalog"
+)
+
+// 目的: class_jobページからジョブ名照合でレベルと経験値を抽出できることを検証する。副作用: なし。前提: 共有クラス（巴術士）は学者と召喚士の両方へ反映される。
+func TestParseClassJobLevels_MatchesJobsByLocalizedName(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`
+<html>
+	<body>
+		<ul class="character__job">
+			<li>
+				<div class="character__job__level">90</div>
+				<div class="character__job__name" data-tooltip="Paladin / Gladiator">Paladin</div>
+				<div class="character__job__exp">-- / --</div>
+			</li>
+			<li>
+				<div class="character__job__level">72</div>
+				<div class="character__job__name">Arcanist</div>
+				<div class="character__job__exp">1,234,567 / 2,345,678</div>
+			</li>
+			<li>
+				<div class="character__job__level">-</div>
+				<div class="character__job__name">Blue Mage</div>
+				<div class="character__job__exp">-- / --</div>
+			</li>
+		</ul>
+	</body>
+</html>`))
+	if err != nil {
+		t.Fatalf("failed to parse html: %v", err)
+	}
+
+	levels, err := ParseClassJobLevels(doc, jobcatalog.Default())
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	if levels["paladin"].Level != 90 || levels["paladin"].HasExp {
+		t.Fatalf("want paladin level 90 without exp, got %+v", levels["paladin"])
+	}
+	for _, jobKey := range []string{"scholar", "summoner"} {
+		level := levels[jobKey]
+		if level.Level != 72 || level.CurrentExp != 1234567 || level.NextExp != 2345678 {
+			t.Fatalf("want %s level 72 with exp, got %+v", jobKey, level)
+		}
+	}
+	if levels["blueMage"].Level != 0 {
+		t.Fatalf("want blueMage level 0, got %d", levels["blueMage"].Level)
+	}
+}
+
+// 目的: FR/DEの女性キャラクターのclass_jobページで、女性形のジョブ名・クラス名からレベルを抽出できることを検証する。副作用: なし。前提: tooltipは`ジョブ / クラス`の女性形で表示される。
+func TestParseClassJobLevels_MatchesFemaleJobNamesInFrenchAndGerman(t *testing.T) {
+	testCases := map[string]string{
+		"fr": `
+<ul class="character__job">
+	<li>
+		<div class="character__job__level">100</div>
+		<div class="character__job__name" data-tooltip="Chevalière noire">Chevalière noire</div>
+		<div class="character__job__exp">-- / --</div>
+	</li>
+	<li>
+		<div class="character__job__level">80</div>
+		<div class="character__job__name" data-tooltip="Mage blanche / Élémentaliste">Mage blanche</div>
+		<div class="character__job__exp">1 234 / 5 678</div>
+	</li>
+</ul>`,
+		"de": `
+<ul class="character__job">
+	<li>
+		<div class="character__job__level">100</div>
+		<div class="character__job__name" data-tooltip="Dunkelritterin">Dunkelritterin</div>
+		<div class="character__job__exp">-- / --</div>
+	</li>
+	<li>
+		<div class="character__job__level">80</div>
+		<div class="character__job__name" data-tooltip="Weißmagierin / Druidin">Weißmagierin</div>
+		<div class="character__job__exp">1.234 / 5.678</div>
+	</li>
+</ul>`,
+	}
+	for region, body := range testCases {
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("failed to parse html: %v", err)
+		}
+		levels, err := ParseClassJobLevels(doc, jobcatalog.Default())
+		if err != nil {
+			t.Fatalf("%s: want no error, got %v", region, err)
+		}
+		if levels["darkKnight"].Level != 100 {
+			t.Fatalf("%s: want darkKnight level 100, got %+v", region, levels["darkKnight"])
+		}
+		if whiteMage := levels["whiteMage"]; whiteMage.Level != 80 || whiteMage.CurrentExp != 1234 || whiteMage.NextExp != 5678 {
+			t.Fatalf("%s: want whiteMage level 80 with exp, got %+v", region, whiteMage)
+		}
+	}
+}
+
+// 目的: ジョブ一覧が見つからないページをエラーとして扱うことを検証する。副作用: なし。前提: Lodestoneのマークアップ変更時に0埋めで返さない。
+func TestParseClassJobLevels_MissingListReturnsError(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<html><body></body></html>`))
+	if err != nil {
+		t.Fatalf("failed to parse html: %v", err)
+	}
+	if _, err := ParseClassJobLevels(doc, jobcatalog.Default()); err == nil {
+		t.Fatalf("want error, got nil")
+	}
+}
@@ -0,0 +1,37 @@
+package lodestone
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// 許可するリダイレクト先のドメイン。Lodestone本体と画像配信（img/img2/lds-img）はいずれもこの配下にある。
+const allowedRedirectDomain = "finalfantasyxiv.com"
+
+// リダイレクトを追う最大回数。http.Clientの既定値と同じにする。
+const maxRedirects = 10
+
+// ErrRedirectNotAllowed は許可ホスト外へのリダイレクトを拒否したことを表す。
+var ErrRedirectNotAllowed = errors.New("redirect is not allowed")
+
+// 目的: http.ClientのCheckRedirectとして、Lodestoneの許可ホスト外へのリダイレクトを拒否する。副作用: なし。前提: 同一ホスト内のリダイレクトは許可し、許可ホストへの移動はhttpsに限る。
+func CheckRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	if len(via) > 0 && strings.EqualFold(req.URL.Host, via[0].URL.Host) {
+		return nil
+	}
+	if isAllowedLodestoneHost(req.URL.Hostname()) && req.URL.Scheme == "https" {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrRedirectNotAllowed, req.URL.Host)
+}
+
+// 目的: ホスト名がLodestoneの許可ドメイン配下か判定する。副作用: なし。前提: ホスト名は大文字小文字を区別しない。
+func isAllowedLodestoneHost(hostName string) bool {
+	hostName = strings.ToLower(hostName)
+	return hostName == allowedRedirectDomain || strings.HasSuffix(hostName, "."+allowedRedirectDomain)
+}
@@ -0,0 +1,99 @@
+package lodestone
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// RetryConfig は一時的な取得失敗の再試行設定。MaxAttemptsは初回を含む試行回数。
+type RetryConfig struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// RetryingFetcher は429/502/503/504と接続リセットを指数バックオフ（ジッタ付き）で再試行する。
+type RetryingFetcher struct {
+	next   Fetcher
+	config RetryConfig
+	sleep  func(ctx context.Context, delay time.Duration) error
+}
+
+// 目的: 再試行付きのFetcherを生成する。副作用: なし。前提: MaxAttemptsが1以下の場合は再試行しない。
+func NewRetryingFetcher(next Fetcher, config RetryConfig) *RetryingFetcher {
+	return &RetryingFetcher{next: next, config: config, sleep: sleepContext}
+}
+
+// 目的: 一時的な失敗を再試行しつつ取得する。副作用: 上流へのHTTPアクセスと待機を行う。前提: Retry-Afterがある場合はその時間を待ち、待機がコンテキストの期限を超える場合やMaxDelayを超える場合は最後のエラーを返す。
+func (f *RetryingFetcher) Fetch(ctx context.Context, targetURL string) (*Page, error) {
+	for attempt := 1; ; attempt++ {
+		page, err := f.next.Fetch(ctx, targetURL)
+		if err == nil || attempt >= f.config.MaxAttempts || !isRetryableFetchError(err) {
+			return page, err
+		}
+		delay, ok := f.retryDelay(err, attempt)
+		if !ok {
+			return nil, err
+		}
+		if deadline, hasDeadline := ctx.Deadline(); hasDeadline && time.Now().Add(delay).After(deadline) {
+			return nil, err
+		}
+		if sleepErr := f.sleep(ctx, delay); sleepErr != nil {
+			return nil, err
+		}
+	}
+}
+
+// 目的: 次の試行までの待機時間を求める。副作用: 乱数を消費する。前提: Retry-AfterがMaxDelayを超える場合は再試行しない（falseを返す）。
+func (f *RetryingFetcher) retryDelay(err error, attempt int) (time.Duration, bool) {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.RetryAfter > 0 {
+		if f.config.MaxDelay > 0 && statusErr.RetryAfter > f.config.MaxDelay {
+			return 0, false
+		}
+		return statusErr.RetryAfter, true
+	}
+	if f.config.BaseDelay <= 0 {
+		return 0, true
+	}
+	backoff := f.config.BaseDelay << (attempt - 1)
+	if f.config.MaxDelay > 0 && (backoff <= 0 || backoff > f.config.MaxDelay) {
+		backoff = f.config.MaxDelay
+	}
+	// 同時に失敗した複数リクエストが同じ間隔で再送しないよう、全区間ジッタを用いる。
+	return time.Duration(rand.Int63n(int64(backoff) + 1)), true
+}
+
+// 目的: 再試行で回復しうる失敗か判定する。副作用: なし。前提: コンテキストの取消・期限切れは再試行しない。
+func isRetryableFetchError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		switch statusErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	// 上流が接続を切った場合、net/httpはECONNRESETまたはEOFを返す。
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// 目的: コンテキストの終了を考慮して待機する。副作用: 呼び出し元をdelayの間ブロックする。前提: コンテキスト終了時はそのエラーを返す。
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
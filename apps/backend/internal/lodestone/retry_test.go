@@ -0,0 +1,129 @@
+package lodestone
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// 目的: 一時的な503の後に成功した場合、再試行で取得できることを検証する。副作用: テスト用HTTPサーバを起動する。前提: 2回目まで503を返す。
+func TestRetryingFetcher_RetriesTransientStatus(t *testing.T) {
+	var calls int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer mockServer.Close()
+
+	fetcher := NewRetryingFetcher(NewHTTPFetcher(mockServer.Client()), RetryConfig{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond})
+	page, err := fetcher.Fetch(context.Background(), mockServer.URL)
+	if err != nil || string(page.Body) != "ok" {
+		t.Fatalf("want success after retries, got err=%v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Fatalf("want 3 attempts, got %d", got)
+	}
+}
+
+// 目的: 404等の恒久的な失敗は再試行しないことを検証する。副作用: テスト用HTTPサーバを起動する。前提: なし。
+func TestRetryingFetcher_DoesNotRetryPermanentStatus(t *testing.T) {
+	var calls int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		http.NotFound(w, nil)
+	}))
+	defer mockServer.Close()
+
+	fetcher := NewRetryingFetcher(NewHTTPFetcher(mockServer.Client()), RetryConfig{MaxAttempts: 3, BaseDelay: time.Millisecond})
+	var statusErr *StatusError
+	if _, err := fetcher.Fetch(context.Background(), mockServer.URL); !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("want 404 status error, got %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("want 1 attempt, got %d", got)
+	}
+}
+
+// 目的: Retry-Afterの秒数だけ待機し、待機がコンテキストの期限を超える場合は再試行しないことを検証する。副作用: テスト用HTTPサーバを起動する。前提: 待機は記録のみで実際には待たない。
+func TestRetryingFetcher_HonorsRetryAfterAndDeadline(t *testing.T) {
+	var calls int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer mockServer.Close()
+
+	fetcher := NewRetryingFetcher(NewHTTPFetcher(mockServer.Client()), RetryConfig{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Second})
+	delays := []time.Duration{}
+	fetcher.sleep = func(_ context.Context, delay time.Duration) error {
+		delays = append(delays, delay)
+		return nil
+	}
+	if _, err := fetcher.Fetch(context.Background(), mockServer.URL); err != nil {
+		t.Fatalf("want success, got %v", err)
+	}
+	if len(delays) != 1 || delays[0] != 2*time.Second {
+		t.Fatalf("want single 2s wait from Retry-After, got %v", delays)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	delays = delays[:0]
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	var statusErr *StatusError
+	if _, err := fetcher.Fetch(ctx, mockServer.URL); !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("want 429 when Retry-After exceeds deadline, got %v", err)
+	}
+	if len(delays) != 0 || atomic.LoadInt32(&calls) != 1 {
+		t.Fatalf("want no wait and 1 attempt, got delays=%v calls=%d", delays, atomic.LoadInt32(&calls))
+	}
+}
+
+// 目的: 上流が接続を切断した場合も再試行することを検証する。副作用: テスト用HTTPサーバを起動する。前提: 初回は応答せずに接続を閉じる。
+func TestRetryingFetcher_RetriesConnectionReset(t *testing.T) {
+	var calls int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				_ = conn.Close()
+			}
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer mockServer.Close()
+
+	fetcher := NewRetryingFetcher(NewHTTPFetcher(mockServer.Client()), RetryConfig{MaxAttempts: 2, BaseDelay: time.Millisecond})
+	if _, err := fetcher.Fetch(context.Background(), mockServer.URL); err != nil {
+		t.Fatalf("want success after reset, got %v", err)
+	}
+}
+
+// 目的: Retry-Afterの秒数とHTTP日付の両形式を解析できることを検証する。副作用: なし。前提: 過去日時は0とする。
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	testCases := map[string]time.Duration{
+		"":                              0,
+		"120":                           2 * time.Minute,
+		"Mon, 01 Jan 2024 00:00:30 GMT": 30 * time.Second,
+		"Sun, 31 Dec 2023 23:59:00 GMT": 0,
+		"soon":                          0,
+	}
+	for value, want := range testCases {
+		if got := parseRetryAfter(value, now); got != want {
+			t.Fatalf("%q: want %s, got %s", value, want, got)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package lodestone
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 称号名を囲む「」。表示言語によっては無いため、無い場合は表示テキスト全体を称号名とする。
+var rewardTitleQuoteRegexp = regexp.MustCompile(`「(.+)」`)
+
+// 男性用と女性用の称号の区切り。
+var rewardTitleSeparators = []string{"／", " / "}
+
+// AchievementReward はアチーブメント達成報酬。性別で称号名が異なる場合はTitleを空にしTitleMan/TitleWomanを設定する。報酬の無い項目は空となる。
+type AchievementReward struct {
+	Title      string   `json:"title,omitempty"`
+	TitleMan   string   `json:"titleMan,omitempty"`
+	TitleWoman string   `json:"titleWoman,omitempty"`
+	ItemName   string   `json:"itemName,omitempty"`
+	ItemURL    string   `json:"itemUrl,omitempty"`
+	Conditions []string `json:"conditions,omitempty"`
+}
+
+// 目的: 報酬ブロックから称号・アイテム・獲得条件を抽出する。副作用: なし。前提: キャラクターのアチーブメント詳細とエオルゼアデータベースで共通のセレクタを使い、報酬ブロックが無い場合は空の報酬を返す。ItemURLはページ上の相対パスのまま返す。
+func parseAchievementReward(sel *SelectorCatalog, scope *goquery.Selection) AchievementReward {
+	block := sel.first(scope, "achievementReward.block")
+	if block.Length() == 0 {
+		return AchievementReward{}
+	}
+	reward := AchievementReward{
+		ItemName: sel.value(block, "achievementReward.itemName"),
+		ItemURL:  sel.value(block, "achievementReward.itemUrl"),
+	}
+	reward.Title, reward.TitleMan, reward.TitleWoman = splitGenderedTitle(sel.value(block, "achievementReward.title"))
+	if conditions := sel.values(block, "achievementReward.condition"); len(conditions) > 0 {
+		reward.Conditions = conditions
+	}
+	return reward
+}
+
+// 目的: 称号の表示テキストを共通の称号名、または男性用と女性用の称号名へ分ける。副作用: なし。前提: 性別で異なる場合は`男性用／女性用`の順に表示され、両者が同じ場合は共通の称号として扱う。
+func splitGenderedTitle(text string) (string, string, string) {
+	title := strings.TrimSpace(text)
+	if matched := rewardTitleQuoteRegexp.FindStringSubmatch(title); len(matched) == 2 {
+		title = strings.TrimSpace(matched[1])
+	}
+	for _, separator := range rewardTitleSeparators {
+		man, woman, found := strings.Cut(title, separator)
+		if !found {
+			continue
+		}
+		man, woman = strings.TrimSpace(man), strings.TrimSpace(woman)
+		if man != "" && woman != "" && man != woman {
+			return "", man, woman
+		}
+		if man != "" {
+			return man, "", ""
+		}
+		return woman, "", ""
+	}
+	return title, "", ""
+}
@@ -0,0 +1,54 @@
+package lodestone
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 目的: 報酬ブロックから性別別の称号・アイテム・獲得条件を抽出することを検証する。副作用: なし。前提: 称号は`男性用／女性用`の順に表示される。
+func TestParseAchievementReward_ExtractsAllRewards(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`
+<div class="db-view__achievement__reward">
+	<p class="db-view__achievement__reward__title">称号「漆黒の英雄／漆黒の女傑」</p>
+	<p class="db-view__achievement__reward__item"><a href="/lodestone/playguide/db/item/abc/">記念の品</a></p>
+	<ul class="db-view__achievement__reward__condition"><li>条件A</li><li>条件B</li></ul>
+</div>`))
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	reward := parseAchievementReward(DefaultSelectorCatalog(), doc.Selection)
+	want := AchievementReward{
+		TitleMan:   "漆黒の英雄",
+		TitleWoman: "漆黒の女傑",
+		ItemName:   "記念の品",
+		ItemURL:    "/lodestone/playguide/db/item/abc/",
+		Conditions: []string{"条件A", "条件B"},
+	}
+	if !reflect.DeepEqual(reward, want) {
+		t.Fatalf("want %+v, got %+v", want, reward)
+	}
+}
+
+// 目的: 称号テキストの分割規則を検証する。副作用: なし。前提: 同じ称号名が並ぶ場合は共通の称号として扱う。
+func TestSplitGenderedTitle(t *testing.T) {
+	cases := []struct {
+		text  string
+		title string
+		man   string
+		woman string
+	}{
+		{text: "称号「冒険者」", title: "冒険者"},
+		{text: "Title: Hero / Heroine", man: "Title: Hero", woman: "Heroine"},
+		{text: "称号「英傑／英傑」", title: "英傑"},
+		{text: "", title: ""},
+	}
+	for _, tc := range cases {
+		title, man, woman := splitGenderedTitle(tc.text)
+		if title != tc.title || man != tc.man || woman != tc.woman {
+			t.Fatalf("%q: want %q/%q/%q, got %q/%q/%q", tc.text, tc.title, tc.man, tc.woman, title, man, woman)
+		}
+	}
+}
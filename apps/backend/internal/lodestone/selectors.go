@@ -0,0 +1,240 @@
+package lodestone
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+)
+
+// 既定のセレクタカタログ。Lodestoneのマークアップ変更時は再ビルドせずLODESTONE_SELECTOR_CATALOG_PATHの外部ファイルで差し替える。
+//
+//go:embed selector_catalog.json
+var defaultSelectorCatalogJSON []byte
+
+const supportedSelectorCatalogVersion = 1
+
+// パーサが参照する全キー。カタログ読み込み時にすべて定義されていることを検証する。
+var requiredSelectorKeys = []string{
+	"character.name",
+	"character.world",
+	"character.title",
+	"character.block",
+	"character.blockName",
+	"character.nameday",
+	"character.grandCompanyIcon",
+	"character.selfIntroduction",
+	"character.portrait",
+	"character.face",
+	"character.freeCompanyPath",
+	"classJob.entry",
+	"classJob.name",
+	"classJob.tooltip",
+	"classJob.level",
+	"classJob.exp",
+	"achievement.list",
+	"achievement.private",
+	"achievement.item",
+	"achievement.entry",
+	"achievement.completed",
+	"achievement.history",
+	"achievement.title",
+	"achievement.time",
+	"achievementDetail.title",
+	"achievementDetail.description",
+	"achievementDetail.icon",
+	"achievementDetail.point",
+	"achievementDetail.latestPatch",
+	"achievementDetail.patch",
+	"achievementDB.row",
+	"achievementDB.link",
+	"achievementDB.name",
+	"achievementDB.nextPage",
+	"achievementDB.categoryLink",
+	"achievementDB.title",
+	"achievementDB.description",
+	"achievementDB.icon",
+	"achievementDB.point",
+	"achievementDB.patch",
+	"achievementReward.block",
+	"achievementReward.title",
+	"achievementReward.itemName",
+	"achievementReward.itemUrl",
+	"achievementReward.condition",
+	"item.name",
+	"item.image",
+	"freeCompany.crest",
+	"freeCompany.crestImage",
+	"freeCompany.name",
+	"freeCompany.tag",
+	"freeCompany.text",
+	"freeCompany.estate",
+	"freeCompany.member",
+	"freeCompany.memberName",
+	"freeCompany.memberPosition",
+	"freeCompany.positionIcon",
+	"freeCompany.positionName",
+	"mount.item",
+	"mount.icon",
+	"mount.name",
+	"mount.total",
+	"mount.empty",
+	"minion.item",
+	"minion.icon",
+	"minion.name",
+	"minion.total",
+	"minion.empty",
+	"page.maintenance",
+	"page.notFound",
+}
+
+// SelectorRule はCSSセレクタと取得する属性の組。Attrが空の場合は要素のテキストを使う。
+type SelectorRule struct {
+	Selector string `json:"selector"`
+	Attr     string `json:"attr,omitempty"`
+}
+
+// SelectorCatalog はキーごとにフォールバック順のルールを保持する。先頭から順に試し、最初に値が得られたルールを採用する。
+type SelectorCatalog struct {
+	Version   int                       `json:"version"`
+	Selectors map[string][]SelectorRule `json:"selectors"`
+}
+
+var currentSelectorCatalog atomic.Pointer[SelectorCatalog]
+
+// 目的: 起動直後から埋め込みカタログで解析できるようにする。副作用: 現在のカタログを設定する。前提: 埋め込みJSONはビルド時に検証済みである。
+func init() {
+	currentSelectorCatalog.Store(DefaultSelectorCatalog())
+}
+
+// 目的: 埋め込み済みの既定セレクタカタログを返す。副作用: なし。前提: 埋め込みJSONは検証済みである。
+func DefaultSelectorCatalog() *SelectorCatalog {
+	catalog, err := ParseSelectorCatalog(defaultSelectorCatalogJSON)
+	if err != nil {
+		panic(fmt.Sprintf("embedded selector catalog is invalid: %v", err))
+	}
+	return catalog
+}
+
+// 目的: ファイルからセレクタカタログを読み込む。副作用: ファイル読み込みを行う。前提: pathが空の場合は既定カタログを使う。
+func LoadSelectorCatalog(path string) (*SelectorCatalog, error) {
+	if strings.TrimSpace(path) == "" {
+		return DefaultSelectorCatalog(), nil
+	}
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSelectorCatalog(body)
+}
+
+// 目的: JSONからセレクタカタログを生成し、バージョン・全キーの定義・セレクタ構文を検証する。副作用: なし。前提: bodyはselector_catalog.json形式である。
+func ParseSelectorCatalog(body []byte) (*SelectorCatalog, error) {
+	var catalog SelectorCatalog
+	if err := json.Unmarshal(body, &catalog); err != nil {
+		return nil, err
+	}
+	if catalog.Version != supportedSelectorCatalogVersion {
+		return nil, fmt.Errorf("selector catalog version %d is not supported", catalog.Version)
+	}
+	for _, key := range requiredSelectorKeys {
+		rules := catalog.Selectors[key]
+		if len(rules) == 0 {
+			return nil, fmt.Errorf("selector %s is missing", key)
+		}
+		for _, rule := range rules {
+			if strings.TrimSpace(rule.Selector) == "" {
+				return nil, fmt.Errorf("selector %s has an empty rule", key)
+			}
+			if _, err := cascadia.Compile(rule.Selector); err != nil {
+				return nil, fmt.Errorf("selector %s is invalid: %w", key, err)
+			}
+		}
+	}
+	return &catalog, nil
+}
+
+// 目的: 以降の解析で使うセレクタカタログを差し替える。副作用: 全パーサの参照先を原子的に切り替える。前提: catalogはParseSelectorCatalogで検証済みである。
+func SetSelectorCatalog(catalog *SelectorCatalog) {
+	currentSelectorCatalog.Store(catalog)
+}
+
+// 目的: 現在のセレクタカタログを返す。副作用: なし。前提: 1回の解析中は同じカタログを使うため、呼び出し側で保持して使う。
+func selectors() *SelectorCatalog {
+	return currentSelectorCatalog.Load()
+}
+
+// 目的: フォールバック順に最初に一致した要素群を返す。副作用: なし。前提: 一致しない場合は空のSelectionを返す。
+func (c *SelectorCatalog) all(scope *goquery.Selection, key string) *goquery.Selection {
+	for _, rule := range c.Selectors[key] {
+		if matched := scope.Find(rule.Selector); matched.Length() > 0 {
+			return matched
+		}
+	}
+	return scope.Slice(0, 0)
+}
+
+// 目的: フォールバック順に最初に一致した先頭要素を返す。副作用: なし。前提: 一致しない場合は空のSelectionを返す。
+func (c *SelectorCatalog) first(scope *goquery.Selection, key string) *goquery.Selection {
+	return c.all(scope, key).First()
+}
+
+// 目的: いずれかのルールに一致する要素が存在するか判定する。副作用: なし。前提: なし。
+func (c *SelectorCatalog) exists(scope *goquery.Selection, key string) bool {
+	return c.all(scope, key).Length() > 0
+}
+
+// 目的: 要素自身がいずれかのルールに一致するか判定する。副作用: なし。前提: 状態クラスの判定に使う。
+func (c *SelectorCatalog) matches(element *goquery.Selection, key string) bool {
+	for _, rule := range c.Selectors[key] {
+		if element.Is(rule.Selector) {
+			return true
+		}
+	}
+	return false
+}
+
+// 目的: 最初に一致したルールの全要素から属性またはテキストを集める。副作用: なし。前提: 空の値は除外する。
+func (c *SelectorCatalog) values(scope *goquery.Selection, key string) []string {
+	values := []string{}
+	for _, rule := range c.Selectors[key] {
+		matched := scope.Find(rule.Selector)
+		if matched.Length() == 0 {
+			continue
+		}
+		matched.Each(func(_ int, element *goquery.Selection) {
+			if trimmed := strings.TrimSpace(ruleValue(element, rule)); trimmed != "" {
+				values = append(values, trimmed)
+			}
+		})
+		return values
+	}
+	return values
+}
+
+// 目的: フォールバック順に属性またはテキストを取得し、最初の空でない値を返す。副作用: なし。前提: 値が得られない場合は空文字を返す。
+func (c *SelectorCatalog) value(scope *goquery.Selection, key string) string {
+	for _, rule := range c.Selectors[key] {
+		element := scope.Find(rule.Selector).First()
+		if element.Length() == 0 {
+			continue
+		}
+		if trimmed := strings.TrimSpace(ruleValue(element, rule)); trimmed != "" {
+			return trimmed
+		}
+	}
+	return ""
+}
+
+// 目的: ルールの指定に従い要素の属性またはテキストを返す。副作用: なし。前提: elementは1要素である。
+func ruleValue(element *goquery.Selection, rule SelectorRule) string {
+	if rule.Attr != "" {
+		return element.AttrOr(rule.Attr, "")
+	}
+	return element.Text()
+}
@@ -0,0 +1,101 @@
+package lodestone
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 目的: テスト用に既定カタログを複製し一部キーを書き換えたJSONを生成する。副作用: なし。前提: mutateはカタログを直接変更する。
+func buildSelectorCatalogJSON(t *testing.T, mutate func(catalog *SelectorCatalog)) []byte {
+	t.Helper()
+	catalog := SelectorCatalog{}
+	if err := json.Unmarshal(defaultSelectorCatalogJSON, &catalog); err != nil {
+		t.Fatalf("failed to decode default catalog: %v", err)
+	}
+	mutate(&catalog)
+	body, err := json.Marshal(catalog)
+	if err != nil {
+		t.Fatalf("failed to encode catalog: %v", err)
+	}
+	return body
+}
+
+// 目的: 埋め込みカタログがパーサの参照する全キーを満たすことを検証する。副作用: なし。前提: なし。
+func TestDefaultSelectorCatalog_DefinesAllRequiredKeys(t *testing.T) {
+	catalog := DefaultSelectorCatalog()
+	for _, key := range requiredSelectorKeys {
+		if len(catalog.Selectors[key]) == 0 {
+			t.Fatalf("want selector %s, got none", key)
+		}
+	}
+}
+
+// 目的: 不完全・非対応・構文不正なカタログを読み込み時に拒否することを検証する。副作用: なし。前提: なし。
+func TestParseSelectorCatalog_RejectsInvalidCatalog(t *testing.T) {
+	testCases := map[string]func(catalog *SelectorCatalog){
+		"missing key": func(catalog *SelectorCatalog) {
+			delete(catalog.Selectors, "character.name")
+		},
+		"unsupported version": func(catalog *SelectorCatalog) {
+			catalog.Version = supportedSelectorCatalogVersion + 1
+		},
+		"invalid selector": func(catalog *SelectorCatalog) {
+			catalog.Selectors["item.name"] = []SelectorRule{{Selector: "div[["}}
+		},
+		"empty rule": func(catalog *SelectorCatalog) {
+			catalog.Selectors["item.image"] = []SelectorRule{{Selector: " ", Attr: "src"}}
+		},
+	}
+	for name, mutate := range testCases {
+		if _, err := ParseSelectorCatalog(buildSelectorCatalogJSON(t, mutate)); err == nil {
+			t.Fatalf("%s: want error, got nil", name)
+		}
+	}
+}
+
+// 目的: ファイルから読み込んだカタログへ差し替えるとパーサの参照セレクタが切り替わることを検証する。副作用: 一時ファイルを作成し、終了時に既定カタログへ戻す。前提: なし。
+func TestSetSelectorCatalog_SwitchesParserSelectors(t *testing.T) {
+	body := buildSelectorCatalogJSON(t, func(catalog *SelectorCatalog) {
+		catalog.Selectors["item.name"] = []SelectorRule{{Selector: ".renamed__item__name"}}
+		catalog.Selectors["item.image"] = []SelectorRule{
+			{Selector: ".renamed__item__image", Attr: "data-src"},
+			{Selector: "meta[property='og:image']", Attr: "content"},
+		}
+	})
+	path := filepath.Join(t.TempDir(), "selector_catalog.json")
+	if err := os.WriteFile(path, body, 0o644); err != nil {
+		t.Fatalf("failed to write catalog: %v", err)
+	}
+	catalog, err := LoadSelectorCatalog(path)
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	SetSelectorCatalog(catalog)
+	defer SetSelectorCatalog(DefaultSelectorCatalog())
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<html><head>
+<meta property="og:image" content="https://img.finalfantasyxiv.com/og.png">
+</head><body>
+<p class="db-view__item__text__name">旧マークアップ</p>
+<p class="renamed__item__name">新マークアップ</p>
+<img class="renamed__item__image" data-src="">
+</body></html>`))
+	if err != nil {
+		t.Fatalf("failed to parse html: %v", err)
+	}
+	item, err := ParseItemDetail(doc)
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	if item.Name != "新マークアップ" {
+		t.Fatalf("want name from renamed selector, got %q", item.Name)
+	}
+	if item.ImageURL != "https://img.finalfantasyxiv.com/og.png" {
+		t.Fatalf("want fallback to og:image when first rule is empty, got %q", item.ImageURL)
+	}
+}
@@ -0,0 +1,96 @@
+package lodestone
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var (
+	ldstStrftimeRegexp   = regexp.MustCompile(`ldst_strftime\(\s*([0-9]+)`)
+	lodestoneDateRegexp  = regexp.MustCompile(`[0-9]{1,4}[/.\-][0-9]{1,2}[/.\-][0-9]{1,4}`)
+	lodestoneRegionHosts = regexp.MustCompile(`^(jp|na|eu|fr|de)\.finalfantasyxiv\.com$`)
+)
+
+// リージョンごとの日付表示形式。スクリプトが無い場合の表示テキスト解析に使う。
+var lodestoneDateLayouts = map[string][]string{
+	"jp": {"2006/01/02", "2006/1/2"},
+	"na": {"01/02/2006", "1/2/2006"},
+	"eu": {"02/01/2006", "2/1/2006"},
+	"fr": {"02/01/2006", "2/1/2006"},
+	"de": {"02.01.2006", "2.1.2006"},
+}
+
+// 目的: LodestoneURLのサブドメインから表示リージョンを求める。副作用: なし。前提: 判別できない場合はjpとして扱う。
+func lodestoneRegion(targetURL string) string {
+	parsedURL, err := url.Parse(targetURL)
+	if err != nil {
+		return "jp"
+	}
+	matched := lodestoneRegionHosts.FindStringSubmatch(parsedURL.Hostname())
+	if matched == nil {
+		return "jp"
+	}
+	return matched[1]
+}
+
+// 目的: スクリプト断片から`ldst_strftime(<epoch>)`のエポック値をすべて抽出する。副作用: なし。前提: 同一要素に複数のスクリプトが含まれる場合がある。
+func extractLdstStrftimeEpochs(script string) []int64 {
+	epochs := []int64{}
+	for _, matched := range ldstStrftimeRegexp.FindAllStringSubmatch(script, -1) {
+		epoch, err := strconv.ParseInt(matched[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		epochs = append(epochs, epoch)
+	}
+	return epochs
+}
+
+// 目的: エポック値をUTC日時へ変換する。副作用: なし。前提: 13桁以上の値はミリ秒単位として扱う。
+func epochToUTC(epoch int64) time.Time {
+	if epoch >= 1e12 {
+		return time.UnixMilli(epoch).UTC()
+	}
+	return time.Unix(epoch, 0).UTC()
+}
+
+// 目的: `ldst_strftime(<epoch>, ...)`スクリプトからUTC日時を取り出す。副作用: なし。前提: 複数ある場合は先頭のエポック値を採用する。
+func parseLodestoneTimestamp(script string) (time.Time, error) {
+	epochs := extractLdstStrftimeEpochs(script)
+	if len(epochs) == 0 {
+		return time.Time{}, errors.New("ldst_strftime timestamp is missing")
+	}
+	return epochToUTC(epochs[0]), nil
+}
+
+// 目的: リージョンの表示形式で書かれた日付テキストをUTC日時へ変換する。副作用: なし。前提: 時刻情報が無いためUTCの0時として扱う。
+func parseLodestoneDateText(text string, region string) (time.Time, error) {
+	dateText := lodestoneDateRegexp.FindString(text)
+	if dateText == "" {
+		return time.Time{}, errors.New("lodestone date text is missing")
+	}
+	layouts := append([]string{}, lodestoneDateLayouts[region]...)
+	layouts = append(layouts, "2006-01-02")
+	for _, layout := range layouts {
+		if parsed, err := time.ParseInLocation(layout, dateText, time.UTC); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, errors.New("lodestone date text format is unknown: " + dateText)
+}
+
+// 目的: 達成日時要素からスクリプト優先で日時を求め、無い場合は表示テキストへフォールバックする。副作用: なし。前提: regionは`jp/na/eu/fr/de`のいずれかである。
+func parseLodestoneTimeElement(timeElement *goquery.Selection, region string) (time.Time, error) {
+	scriptText := timeElement.Find("script").Text()
+	if completedDate, err := parseLodestoneTimestamp(scriptText); err == nil {
+		return completedDate, nil
+	}
+	displayElement := timeElement.Clone()
+	displayElement.Find("script").Remove()
+	return parseLodestoneDateText(displayElement.Text(), region)
+}
@@ -0,0 +1,86 @@
+package lodestone
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// 目的: 複数のldst_strftimeスクリプトからエポック値をすべて抽出できることを検証する。副作用: なし。前提: 引数の空白や書式指定は無視する。
+func TestExtractLdstStrftimeEpochs_ReturnsAllEpochs(t *testing.T) {
+	script := `document.getElementById('datetime-a').innerHTML = ldst_strftime(1600000000, 'YMD');
+document.getElementById('datetime-b').innerHTML = ldst_strftime( 1700000000 , 'YMDHM');`
+	epochs := extractLdstStrftimeEpochs(script)
+	if len(epochs) != 2 || epochs[0] != 1600000000 || epochs[1] != 1700000000 {
+		t.Fatalf("want two epochs, got %v", epochs)
+	}
+}
+
+// 目的: エポック秒とミリ秒のどちらもUTC日時へ正しく変換できることを検証する。副作用: なし。前提: 13桁以上はミリ秒として扱う。
+func TestParseLodestoneTimestamp_ReturnsUTC(t *testing.T) {
+	want := time.Date(2020, 9, 13, 12, 26, 40, 0, time.UTC)
+	for _, script := range []string{"ldst_strftime(1600000000, 'YMD');", "ldst_strftime(1600000000000, 'YMD');"} {
+		got, err := parseLodestoneTimestamp(script)
+		if err != nil {
+			t.Fatalf("want no error for %s, got %v", script, err)
+		}
+		if !got.Equal(want) || got.Location() != time.UTC {
+			t.Fatalf("want %v in UTC for %s, got %v", want, script, got)
+		}
+	}
+}
+
+// 目的: スクリプトが無い場合にリージョン別の日付テキストへフォールバックすることを検証する。副作用: なし。前提: na/euでは月日の並びが異なる。
+func TestParseLodestoneTimeElement_FallsBackToLocalizedText(t *testing.T) {
+	testCases := []struct {
+		region string
+		text   string
+	}{
+		{region: "jp", text: "2023/05/01"},
+		{region: "na", text: "05/01/2023"},
+		{region: "eu", text: "01/05/2023"},
+		{region: "fr", text: "01/05/2023"},
+		{region: "de", text: "01.05.2023"},
+	}
+	want := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	for _, testCase := range testCases {
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<time class="entry__activity__time"><span>` + testCase.text + `</span></time>`))
+		if err != nil {
+			t.Fatalf("failed to parse html: %v", err)
+		}
+		got, err := parseLodestoneTimeElement(doc.Find(".entry__activity__time"), testCase.region)
+		if err != nil {
+			t.Fatalf("want no error for %s, got %v", testCase.region, err)
+		}
+		if !got.Equal(want) {
+			t.Fatalf("want %v for %s, got %v", want, testCase.region, got)
+		}
+	}
+}
+
+// 目的: スクリプトがある場合は表示テキストより優先されることを検証する。副作用: なし。前提: スクリプトのエポック値が正である。
+func TestParseLodestoneTimeElement_PrefersScript(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<time class="entry__activity__time"><span id="datetime-a">01/05/2023</span><script>document.getElementById('datetime-a').innerHTML = ldst_strftime(1600000000, 'YMD');</script></time>`))
+	if err != nil {
+		t.Fatalf("failed to parse html: %v", err)
+	}
+	got, err := parseLodestoneTimeElement(doc.Find(".entry__activity__time"), "na")
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	if !got.Equal(time.Unix(1600000000, 0)) {
+		t.Fatalf("want script timestamp, got %v", got)
+	}
+}
+
+// 目的: URLのサブドメインから表示リージョンを判定できることを検証する。副作用: なし。前提: 判別不能時はjpとなる。
+func TestLodestoneRegion_ResolvesFromHost(t *testing.T) {
+	if region := lodestoneRegion("https://de.finalfantasyxiv.com/lodestone/character/1/"); region != "de" {
+		t.Fatalf("want de, got %s", region)
+	}
+	if region := lodestoneRegion("http://127.0.0.1:8080/lodestone/character/1/"); region != "jp" {
+		t.Fatalf("want jp fallback, got %s", region)
+	}
+}
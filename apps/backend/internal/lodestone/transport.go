@@ -0,0 +1,157 @@
+package lodestone
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// HostLimitConfig はホストごとの送信上限。0以下の項目は制限しない。
+type HostLimitConfig struct {
+	MaxConcurrency    int
+	RequestsPerSecond float64
+}
+
+// HostStats はホストごとの送信待ち行列の状態。Abandonedは順番待ち中にコンテキストが終了した件数。
+type HostStats struct {
+	Host      string        `json:"host"`
+	Waiting   int64         `json:"waiting"`
+	InFlight  int64         `json:"inFlight"`
+	Started   int64         `json:"started"`
+	Abandoned int64         `json:"abandoned"`
+	TotalWait time.Duration `json:"totalWait"`
+}
+
+// HostLimitedTransport はLodestoneへの送信を同時実行数と毎秒リクエスト数でホストごとに制限する。アチーブメント種別やFCメンバーページの並行取得でブロックされないよう、共有http.ClientのTransportとして使う。
+type HostLimitedTransport struct {
+	next   http.RoundTripper
+	config HostLimitConfig
+	mu     sync.Mutex
+	hosts  map[string]*hostLimiter
+}
+
+type hostLimiter struct {
+	slots     chan struct{}
+	limiter   *rate.Limiter
+	waiting   atomic.Int64
+	inFlight  atomic.Int64
+	started   atomic.Int64
+	abandoned atomic.Int64
+	waitNanos atomic.Int64
+}
+
+// 目的: ホストごとの送信制限付きTransportを生成する。副作用: なし。前提: nextがnilの場合はhttp.DefaultTransportを使う。
+func NewHostLimitedTransport(next http.RoundTripper, config HostLimitConfig) *HostLimitedTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &HostLimitedTransport{next: next, config: config, hosts: map[string]*hostLimiter{}}
+}
+
+// 目的: 送信枠と送信間隔を確保してからリクエストを送る。副作用: 上流へHTTPリクエストを送信し待ち行列の統計を更新する。前提: 送信枠はレスポンス本文のClose時に返却される。
+func (t *HostLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := t.hostLimiter(req.URL.Host)
+	waitStartedAt := time.Now()
+	host.waiting.Add(1)
+	err := host.acquire(req.Context())
+	host.waiting.Add(-1)
+	host.waitNanos.Add(int64(time.Since(waitStartedAt)))
+	if err != nil {
+		host.abandoned.Add(1)
+		return nil, err
+	}
+	host.started.Add(1)
+	host.inFlight.Add(1)
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		host.release()
+		return nil, err
+	}
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: host.release}
+	return resp, nil
+}
+
+// 目的: 全ホストの待ち行列の状態をホスト名順で返す。副作用: なし。前提: 値は取得時点のスナップショットである。
+func (t *HostLimitedTransport) Stats() []HostStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stats := make([]HostStats, 0, len(t.hosts))
+	for name, host := range t.hosts {
+		stats = append(stats, HostStats{
+			Host:      name,
+			Waiting:   host.waiting.Load(),
+			InFlight:  host.inFlight.Load(),
+			Started:   host.started.Load(),
+			Abandoned: host.abandoned.Load(),
+			TotalWait: time.Duration(host.waitNanos.Load()),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Host < stats[j].Host })
+	return stats
+}
+
+// 目的: ホスト名に対応する制限器を返し、無ければ生成する。副作用: hostsへ追加する。前提: ホスト名は大文字小文字を区別しない。
+func (t *HostLimitedTransport) hostLimiter(hostName string) *hostLimiter {
+	key := strings.ToLower(hostName)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if host, ok := t.hosts[key]; ok {
+		return host
+	}
+	host := &hostLimiter{limiter: rate.NewLimiter(rate.Inf, 1)}
+	if t.config.MaxConcurrency > 0 {
+		host.slots = make(chan struct{}, t.config.MaxConcurrency)
+	}
+	if t.config.RequestsPerSecond > 0 {
+		host.limiter = rate.NewLimiter(rate.Limit(t.config.RequestsPerSecond), 1)
+	}
+	t.hosts[key] = host
+	return host
+}
+
+// 目的: 同時実行枠と送信間隔を順に確保する。副作用: 枠を1つ占有する。前提: コンテキスト終了時は確保済みの枠を返却してエラーを返す。
+func (h *hostLimiter) acquire(ctx context.Context) error {
+	if h.slots != nil {
+		select {
+		case h.slots <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if err := h.limiter.Wait(ctx); err != nil {
+		if h.slots != nil {
+			<-h.slots
+		}
+		return err
+	}
+	return nil
+}
+
+// 目的: 占有中の同時実行枠を返却する。副作用: inFlightを減らす。前提: acquireに成功したリクエストごとに1回だけ呼ばれる。
+func (h *hostLimiter) release() {
+	h.inFlight.Add(-1)
+	if h.slots != nil {
+		<-h.slots
+	}
+}
+
+// releasingBody は本文のClose時に1回だけ送信枠を返却する。
+type releasingBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+// 目的: 本文を閉じ送信枠を返却する。副作用: 送信枠を返却する。前提: 複数回呼ばれても返却は1回のみである。
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
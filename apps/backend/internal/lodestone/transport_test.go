@@ -0,0 +1,102 @@
+package lodestone
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// 目的: ホストごとの同時実行数が上限を超えないことと統計が記録されることを検証する。副作用: テスト用HTTPサーバを起動する。前提: 上限は2とする。
+func TestHostLimitedTransport_CapsConcurrencyPerHost(t *testing.T) {
+	var current, peak int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		now := atomic.AddInt32(&current, 1)
+		for {
+			observed := atomic.LoadInt32(&peak)
+			if now <= observed || atomic.CompareAndSwapInt32(&peak, observed, now) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&current, -1)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer mockServer.Close()
+
+	transport := NewHostLimitedTransport(mockServer.Client().Transport, HostLimitConfig{MaxConcurrency: 2})
+	fetcher := NewHTTPFetcher(&http.Client{Transport: transport})
+	var waitGroup sync.WaitGroup
+	for index := 0; index < 6; index++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			if _, err := fetcher.Fetch(context.Background(), mockServer.URL+"/lodestone/character/1/"); err != nil {
+				t.Errorf("want no error, got %v", err)
+			}
+		}()
+	}
+	waitGroup.Wait()
+
+	if got := atomic.LoadInt32(&peak); got != 2 {
+		t.Fatalf("want peak concurrency 2, got %d", got)
+	}
+	stats := transport.Stats()
+	if len(stats) != 1 || stats[0].Started != 6 || stats[0].InFlight != 0 || stats[0].Waiting != 0 {
+		t.Fatalf("want 6 started and nothing pending, got %+v", stats)
+	}
+}
+
+// 目的: 順番待ち中にリクエストのコンテキストが期限切れになるとエラーを返し、待ち行列から外れることを検証する。副作用: テスト用HTTPサーバを起動する。前提: 同時実行数1で先行リクエストが処理中である。
+func TestHostLimitedTransport_QueuedRequestHonorsContextDeadline(t *testing.T) {
+	release := make(chan struct{})
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		<-release
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer mockServer.Close()
+	defer close(release)
+
+	transport := NewHostLimitedTransport(mockServer.Client().Transport, HostLimitConfig{MaxConcurrency: 1})
+	fetcher := NewHTTPFetcher(&http.Client{Transport: transport})
+	go func() {
+		_, _ = fetcher.Fetch(context.Background(), mockServer.URL+"/first")
+	}()
+	for len(transport.Stats()) == 0 || transport.Stats()[0].InFlight == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	if _, err := fetcher.Fetch(ctx, mockServer.URL+"/second"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("want context.DeadlineExceeded, got %v", err)
+	}
+	stats := transport.Stats()[0]
+	if stats.Abandoned != 1 || stats.Waiting != 0 || stats.Started != 1 {
+		t.Fatalf("want 1 abandoned and 1 started, got %+v", stats)
+	}
+}
+
+// 目的: 毎秒リクエスト数の上限に従い送信間隔が空くことを検証する。副作用: テスト用HTTPサーバを起動する。前提: 20件/秒のため3件目は約100ms後となる。
+func TestHostLimitedTransport_SpacesRequestsPerSecond(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer mockServer.Close()
+
+	transport := NewHostLimitedTransport(mockServer.Client().Transport, HostLimitConfig{RequestsPerSecond: 20})
+	fetcher := NewHTTPFetcher(&http.Client{Transport: transport})
+	startedAt := time.Now()
+	for index := 0; index < 3; index++ {
+		if _, err := fetcher.Fetch(context.Background(), mockServer.URL+"/"); err != nil {
+			t.Fatalf("want no error, got %v", err)
+		}
+	}
+	if elapsed := time.Since(startedAt); elapsed < 90*time.Millisecond {
+		t.Fatalf("want requests spaced by rate limit, got %s", elapsed)
+	}
+}
@@ -0,0 +1,81 @@
+package masterdata
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// editedAchievementData と同じ命名規則。route/categoryはそのまま保存パスに使う。
+var pathSegmentRegexp = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// CategorySource は編集画面のroute/categoryと、対応するエオルゼアデータベースのアチーブメント一覧URLの組。ListURLが空の項目はResolveListURLsで一覧ページの絞り込みリンクから導出し、導出できない項目はクロールしない。LodestoneNameはカテゴリの英語表記がcategoryと一致しない場合のみ設定する。
+type CategorySource struct {
+	Route         string `json:"route"`
+	Category      string `json:"category"`
+	ListURL       string `json:"listUrl,omitempty"`
+	LodestoneName string `json:"lodestoneName,omitempty"`
+}
+
+// Config はクロール対象の対応表。Lodestoneの一覧URL（category2/category3等）は通常は導出し、固定したい場合のみ運用者が設定する。
+type Config struct {
+	Categories []CategorySource `json:"categories"`
+}
+
+// 目的: ファイルからクロール対象の対応表を読み込む。副作用: ファイル読み込みを行う。前提: pathはConfig形式のJSONである。
+func LoadConfig(path string) (*Config, error) {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseConfig(body)
+}
+
+// 目的: JSONから対応表を生成し、保存パスと一覧URLを検証する。副作用: なし。前提: route/categoryの重複は許可しない。
+func ParseConfig(body []byte) (*Config, error) {
+	var config Config
+	if err := json.Unmarshal(body, &config); err != nil {
+		return nil, err
+	}
+	if len(config.Categories) == 0 {
+		return nil, errors.New("categories are empty")
+	}
+	seen := map[string]bool{}
+	for _, source := range config.Categories {
+		if !pathSegmentRegexp.MatchString(source.Route) || !pathSegmentRegexp.MatchString(source.Category) {
+			return nil, fmt.Errorf("invalid route/category: %s/%s", source.Route, source.Category)
+		}
+		if seen[source.key()] {
+			return nil, fmt.Errorf("duplicated route/category: %s", source.key())
+		}
+		seen[source.key()] = true
+		if source.ListURL != "" && !isAchievementDBListURL(source.ListURL) {
+			return nil, fmt.Errorf("invalid listUrl for %s: %s", source.key(), source.ListURL)
+		}
+	}
+	return &config, nil
+}
+
+// 目的: 状態ファイルと集計で使う`route/category`形式のキーを返す。副作用: なし。前提: なし。
+func (s CategorySource) key() string {
+	return s.Route + "/" + s.Category
+}
+
+// 目的: 保存先のマスターデータパスを返す。副作用: なし。前提: route/categoryは検証済みである。
+func (s CategorySource) storagePath() string {
+	return fmt.Sprintf("achievementMasterData/%s/%s.json", s.Route, s.Category)
+}
+
+// 目的: URLがLodestoneのエオルゼアデータベースのアチーブメント一覧か判定する。副作用: なし。前提: httpsのみ許可する。
+func isAchievementDBListURL(rawURL string) bool {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil || parsedURL.Scheme != "https" {
+		return false
+	}
+	host := strings.ToLower(parsedURL.Hostname())
+	return strings.HasSuffix(host, ".finalfantasyxiv.com") && strings.HasPrefix(parsedURL.Path, "/lodestone/playguide/db/achievement/")
+}
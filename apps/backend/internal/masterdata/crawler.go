@@ -0,0 +1,247 @@
+package masterdata
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ff14/achievement-backend/internal/lodestone"
+)
+
+// 一覧ページを辿る上限。次ページリンクの循環やマークアップ変化で終わらなくなることを防ぐ。
+const maxListPages = 100
+
+// カテゴリごとの処理結果。
+const (
+	StatusUpdated   = "updated"
+	StatusUnchanged = "unchanged"
+	StatusUnmapped  = "unmapped"
+	StatusFailed    = "failed"
+)
+
+// Source はクロールで使うLodestone取得。lodestone.HTTPClientが満たす。
+type Source interface {
+	FetchAchievementDBList(ctx context.Context, listURL string) (*lodestone.AchievementDBList, error)
+	FetchAchievementDBDetail(ctx context.Context, detailURL string) (*lodestone.AchievementDBDetail, error)
+}
+
+// TextStorage はマスターデータの保存先。storage.FileTextStorage/GCSStorageが満たす。
+type TextStorage interface {
+	SaveText(ctx context.Context, path string, body []byte) error
+}
+
+// MasterAchievement はマスターデータのアチーブメント1件。IDはエオルゼアデータベースのIDである。
+type MasterAchievement struct {
+	ID          string                      `json:"id"`
+	URL         string                      `json:"url"`
+	Title       string                      `json:"title"`
+	Description string                      `json:"description"`
+	IconURL     string                      `json:"iconUrl"`
+	Point       int                         `json:"point"`
+	Reward      lodestone.AchievementReward `json:"reward"`
+}
+
+// MasterDataFile は`achievementMasterData/{route}/{category}.json`の内容。
+type MasterDataFile struct {
+	Route        string              `json:"route"`
+	Category     string              `json:"category"`
+	SourceURL    string              `json:"sourceUrl"`
+	CrawledAt    time.Time           `json:"crawledAt"`
+	Achievements []MasterAchievement `json:"achievements"`
+}
+
+// CategorySummary はカテゴリ1件の前回保存時からの差分。Added/Changed/Removedはアチーブメント名の一覧。
+type CategorySummary struct {
+	Route    string   `json:"route"`
+	Category string   `json:"category"`
+	Status   string   `json:"status"`
+	Total    int      `json:"total"`
+	Added    []string `json:"added,omitempty"`
+	Changed  []string `json:"changed,omitempty"`
+	Removed  []string `json:"removed,omitempty"`
+	Resumed  bool     `json:"resumed,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// Summary は1回のクロール実行の結果。再開した実行では前回までに完了したカテゴリも含む。
+type Summary struct {
+	StartedAt  time.Time         `json:"startedAt"`
+	FinishedAt time.Time         `json:"finishedAt"`
+	Categories []CategorySummary `json:"categories"`
+}
+
+// 目的: 失敗したカテゴリ数を返す。副作用: なし。前提: なし。
+func (s *Summary) Failed() int {
+	failed := 0
+	for _, category := range s.Categories {
+		if category.Status == StatusFailed {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Crawler はエオルゼアデータベースのアチーブメント一覧をカテゴリごとに辿りマスターデータを保存する。
+type Crawler struct {
+	source    Source
+	storage   TextStorage
+	statePath string
+	now       func() time.Time
+}
+
+// 目的: クローラを生成する。副作用: なし。前提: statePathは再開用の状態を保存するローカルファイルパスである。
+func NewCrawler(source Source, storage TextStorage, statePath string) *Crawler {
+	return &Crawler{source: source, storage: storage, statePath: statePath, now: time.Now}
+}
+
+// 目的: 全カテゴリをクロールし、内容が変わったカテゴリのマスターデータを保存して差分を返す。副作用: 外部サイトへHTTPアクセスし、ストレージと状態ファイルへ書き込む。前提: 中断・失敗した実行はrestartがfalseなら完了済みカテゴリを飛ばして再開する。
+func (c *Crawler) Run(ctx context.Context, sources []CategorySource, restart bool) (*Summary, error) {
+	state, err := loadState(c.statePath)
+	if err != nil {
+		return nil, err
+	}
+	if restart || state.Run == nil {
+		state.Run = &runState{StartedAt: c.now(), Completed: map[string]CategorySummary{}}
+	}
+	summary := &Summary{StartedAt: state.Run.StartedAt, Categories: []CategorySummary{}}
+	for _, source := range sources {
+		if completed, ok := state.Run.Completed[source.key()]; ok {
+			completed.Resumed = true
+			summary.Categories = append(summary.Categories, completed)
+			continue
+		}
+		if source.ListURL == "" {
+			summary.Categories = append(summary.Categories, CategorySummary{Route: source.Route, Category: source.Category, Status: StatusUnmapped})
+			continue
+		}
+		categorySummary, err := c.crawlCategory(ctx, source, state)
+		if err != nil {
+			if ctx.Err() != nil {
+				return summary, ctx.Err()
+			}
+			summary.Categories = append(summary.Categories, CategorySummary{Route: source.Route, Category: source.Category, Status: StatusFailed, Error: err.Error()})
+			continue
+		}
+		state.Run.Completed[source.key()] = categorySummary
+		if err := saveState(c.statePath, state); err != nil {
+			return summary, err
+		}
+		summary.Categories = append(summary.Categories, categorySummary)
+	}
+	summary.FinishedAt = c.now()
+	if summary.Failed() == 0 {
+		state.Run = nil
+	}
+	return summary, saveState(c.statePath, state)
+}
+
+// 目的: 1カテゴリの一覧と詳細を取得し、前回との差分がある場合のみマスターデータを保存する。副作用: 外部サイトへHTTPアクセスしストレージへ書き込み、stateの要約を更新する。前提: 1件でも取得に失敗した場合は保存せずエラーを返す。
+func (c *Crawler) crawlCategory(ctx context.Context, source CategorySource, state *crawlState) (CategorySummary, error) {
+	entries, err := c.collectEntries(ctx, source.ListURL)
+	if err != nil {
+		return CategorySummary{}, err
+	}
+	achievements := make([]MasterAchievement, 0, len(entries))
+	for _, entry := range entries {
+		detail, err := c.source.FetchAchievementDBDetail(ctx, entry.URL)
+		if err != nil {
+			return CategorySummary{}, fmt.Errorf("%s: %w", entry.URL, err)
+		}
+		achievements = append(achievements, MasterAchievement{
+			ID:          entry.ID,
+			URL:         entry.URL,
+			Title:       detail.Title,
+			Description: detail.Description,
+			IconURL:     detail.IconURL,
+			Point:       detail.Point,
+			Reward:      detail.Reward,
+		})
+	}
+	previous, existed := state.Categories[source.key()]
+	digests, summary := diffAchievements(previous, achievements)
+	summary.Route, summary.Category = source.Route, source.Category
+	summary.Status = StatusUnchanged
+	if !existed || len(summary.Added) > 0 || len(summary.Changed) > 0 || len(summary.Removed) > 0 {
+		body, err := json.MarshalIndent(MasterDataFile{
+			Route:        source.Route,
+			Category:     source.Category,
+			SourceURL:    source.ListURL,
+			CrawledAt:    c.now().UTC(),
+			Achievements: achievements,
+		}, "", "  ")
+		if err != nil {
+			return CategorySummary{}, err
+		}
+		if err := c.storage.SaveText(ctx, source.storagePath(), body); err != nil {
+			return CategorySummary{}, err
+		}
+		summary.Status = StatusUpdated
+	}
+	state.Categories[source.key()] = digests
+	return summary, nil
+}
+
+// 目的: 一覧の全ページを辿りアチーブメントの詳細URLを集める。副作用: 外部サイトへHTTPアクセスする。前提: 複数ページに同じIDが現れた場合は最初の1件のみ使う。
+func (c *Crawler) collectEntries(ctx context.Context, listURL string) ([]lodestone.AchievementDBEntry, error) {
+	entries := []lodestone.AchievementDBEntry{}
+	seenIDs := map[string]bool{}
+	visitedPages := map[string]bool{}
+	for pageURL := listURL; pageURL != "" && !visitedPages[pageURL]; {
+		if len(visitedPages) >= maxListPages {
+			return nil, fmt.Errorf("list exceeds %d pages: %s", maxListPages, listURL)
+		}
+		visitedPages[pageURL] = true
+		list, err := c.source.FetchAchievementDBList(ctx, pageURL)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", pageURL, err)
+		}
+		for _, entry := range list.Entries {
+			if seenIDs[entry.ID] {
+				continue
+			}
+			seenIDs[entry.ID] = true
+			entries = append(entries, entry)
+		}
+		pageURL = list.NextPageURL
+	}
+	return entries, nil
+}
+
+// 目的: 前回の要約と今回の取得結果から追加・変更・削除を求める。副作用: なし。前提: 削除分はID順で返す。
+func diffAchievements(previous map[string]achievementDigest, achievements []MasterAchievement) (map[string]achievementDigest, CategorySummary) {
+	digests := make(map[string]achievementDigest, len(achievements))
+	summary := CategorySummary{Total: len(achievements)}
+	for _, achievement := range achievements {
+		digest := achievementDigest{Title: achievement.Title, Hash: hashAchievement(achievement)}
+		digests[achievement.ID] = digest
+		before, ok := previous[achievement.ID]
+		switch {
+		case !ok:
+			summary.Added = append(summary.Added, achievement.Title)
+		case before.Hash != digest.Hash:
+			summary.Changed = append(summary.Changed, achievement.Title)
+		}
+	}
+	removedIDs := []string{}
+	for id := range previous {
+		if _, ok := digests[id]; !ok {
+			removedIDs = append(removedIDs, id)
+		}
+	}
+	sort.Strings(removedIDs)
+	for _, id := range removedIDs {
+		summary.Removed = append(summary.Removed, previous[id].Title)
+	}
+	return digests, summary
+}
+
+// 目的: アチーブメント1件の内容ハッシュを求める。副作用: なし。前提: JSONエンコード結果はフィールド順で決まるため安定する。
+func hashAchievement(achievement MasterAchievement) string {
+	body, _ := json.Marshal(achievement)
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
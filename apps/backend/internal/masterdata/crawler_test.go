@@ -0,0 +1,187 @@
+package masterdata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ff14/achievement-backend/internal/lodestone"
+)
+
+// stubSource は一覧ページと詳細をURLごとに返すSource。failURLsに含まれるURLは取得失敗とする。
+type stubSource struct {
+	lists    map[string]*lodestone.AchievementDBList
+	details  map[string]*lodestone.AchievementDBDetail
+	failURLs map[string]bool
+	calls    int
+}
+
+// 目的: URLに対応する一覧を返す。副作用: 呼び出し回数を記録する。前提: 未登録URLはエラーとする。
+func (s *stubSource) FetchAchievementDBList(_ context.Context, listURL string) (*lodestone.AchievementDBList, error) {
+	s.calls++
+	if list, ok := s.lists[listURL]; ok && !s.failURLs[listURL] {
+		return list, nil
+	}
+	return nil, errors.New("list not found")
+}
+
+// 目的: URLに対応する詳細を返す。副作用: 呼び出し回数を記録する。前提: 未登録URLはエラーとする。
+func (s *stubSource) FetchAchievementDBDetail(_ context.Context, detailURL string) (*lodestone.AchievementDBDetail, error) {
+	s.calls++
+	if detail, ok := s.details[detailURL]; ok && !s.failURLs[detailURL] {
+		return detail, nil
+	}
+	return nil, errors.New("detail not found")
+}
+
+// memoryStorage は保存内容をパスごとに保持するTextStorage。
+type memoryStorage struct {
+	saved map[string][]byte
+}
+
+// 目的: 保存内容を記録する。副作用: savedを更新する。前提: なし。
+func (s *memoryStorage) SaveText(_ context.Context, path string, body []byte) error {
+	s.saved[path] = body
+	return nil
+}
+
+// 目的: 2ページの一覧と3件の詳細を持つSourceを生成する。副作用: なし。前提: 2ページ目にも1ページ目と同じIDが重複して表示される。
+func newStubSource() *stubSource {
+	return &stubSource{
+		lists: map[string]*lodestone.AchievementDBList{
+			"list/battle": {
+				Entries:     []lodestone.AchievementDBEntry{{ID: "a", URL: "detail/a"}, {ID: "b", URL: "detail/b"}},
+				NextPageURL: "list/battle?page=2",
+			},
+			"list/battle?page=2": {
+				Entries: []lodestone.AchievementDBEntry{{ID: "b", URL: "detail/b"}, {ID: "c", URL: "detail/c"}},
+			},
+			"list/quests": {Entries: []lodestone.AchievementDBEntry{{ID: "q", URL: "detail/q"}}},
+		},
+		details: map[string]*lodestone.AchievementDBDetail{
+			"detail/a": {Title: "A", Description: "a", IconURL: "icon/a", Point: 5},
+			"detail/b": {Title: "B", Description: "b", IconURL: "icon/b", Point: 10, Reward: lodestone.AchievementReward{Title: "称号B"}},
+			"detail/c": {Title: "C", Description: "c", IconURL: "icon/c", Point: 10},
+			"detail/q": {Title: "Q", Description: "q", IconURL: "icon/q", Point: 5},
+		},
+		failURLs: map[string]bool{},
+	}
+}
+
+var testSources = []CategorySource{
+	{Route: "battle", Category: "dungeons", ListURL: "list/battle"},
+	{Route: "quests", Category: "quest", ListURL: "list/quests"},
+	{Route: "pvp", Category: "frontline"},
+}
+
+// 目的: 全ページを辿って保存し、2回目は追加・変更・削除のあったカテゴリのみ保存し直すことを検証する。副作用: 一時ディレクトリへ状態ファイルを書き込む。前提: ListURLが空のカテゴリはunmappedとなる。
+func TestCrawler_WritesMasterDataAndSummarizesChanges(t *testing.T) {
+	source := newStubSource()
+	storage := &memoryStorage{saved: map[string][]byte{}}
+	crawler := NewCrawler(source, storage, filepath.Join(t.TempDir(), "state.json"))
+
+	summary, err := crawler.Run(context.Background(), testSources, false)
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	var file MasterDataFile
+	if err := json.Unmarshal(storage.saved["achievementMasterData/battle/dungeons.json"], &file); err != nil {
+		t.Fatalf("failed to unmarshal master data: %v", err)
+	}
+	if len(file.Achievements) != 3 || file.Achievements[1].Reward.Title != "称号B" {
+		t.Fatalf("want 3 deduplicated achievements with reward, got %+v", file.Achievements)
+	}
+	if got := summary.Categories[0]; got.Status != StatusUpdated || !reflect.DeepEqual(got.Added, []string{"A", "B", "C"}) {
+		t.Fatalf("want all added on first run, got %+v", got)
+	}
+	if summary.Categories[2].Status != StatusUnmapped {
+		t.Fatalf("want unmapped category, got %+v", summary.Categories[2])
+	}
+
+	delete(storage.saved, "achievementMasterData/quests/quest.json")
+	source.details["detail/a"] = &lodestone.AchievementDBDetail{Title: "A", Description: "a", IconURL: "icon/a", Point: 10}
+	source.lists["list/battle?page=2"].Entries = source.lists["list/battle?page=2"].Entries[:1]
+	source.lists["list/battle"].Entries = append(source.lists["list/battle"].Entries, lodestone.AchievementDBEntry{ID: "d", URL: "detail/d"})
+	source.details["detail/d"] = &lodestone.AchievementDBDetail{Title: "D", Description: "d", IconURL: "icon/d"}
+
+	summary, err = crawler.Run(context.Background(), testSources, false)
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	want := CategorySummary{Route: "battle", Category: "dungeons", Status: StatusUpdated, Total: 3, Added: []string{"D"}, Changed: []string{"A"}, Removed: []string{"C"}}
+	if !reflect.DeepEqual(summary.Categories[0], want) {
+		t.Fatalf("want %+v, got %+v", want, summary.Categories[0])
+	}
+	if summary.Categories[1].Status != StatusUnchanged {
+		t.Fatalf("want unchanged category, got %+v", summary.Categories[1])
+	}
+	if _, ok := storage.saved["achievementMasterData/quests/quest.json"]; ok {
+		t.Fatalf("want unchanged category not rewritten")
+	}
+}
+
+// 目的: 失敗したカテゴリのみを次回実行で取得し直し、完了済みカテゴリは再取得しないことを検証する。副作用: 一時ディレクトリへ状態ファイルを書き込む。前提: 1回目はquestsの詳細取得が失敗する。
+func TestCrawler_ResumesFailedRun(t *testing.T) {
+	source := newStubSource()
+	source.failURLs["detail/q"] = true
+	storage := &memoryStorage{saved: map[string][]byte{}}
+	crawler := NewCrawler(source, storage, filepath.Join(t.TempDir(), "state.json"))
+
+	summary, err := crawler.Run(context.Background(), testSources, false)
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	if summary.Failed() != 1 || summary.Categories[1].Error == "" {
+		t.Fatalf("want quests failed, got %+v", summary.Categories)
+	}
+	if _, ok := storage.saved["achievementMasterData/quests/quest.json"]; ok {
+		t.Fatalf("want failed category not saved")
+	}
+
+	delete(source.failURLs, "detail/q")
+	source.calls = 0
+	summary, err = crawler.Run(context.Background(), testSources, false)
+	if err != nil || summary.Failed() != 0 {
+		t.Fatalf("want resumed run to succeed, got %v %+v", err, summary)
+	}
+	if source.calls != 2 {
+		t.Fatalf("want only quests fetched on resume, got %d calls", source.calls)
+	}
+	if !summary.Categories[0].Resumed || summary.Categories[1].Status != StatusUpdated {
+		t.Fatalf("want battle resumed and quests updated, got %+v", summary.Categories)
+	}
+
+	source.calls = 0
+	if _, err := crawler.Run(context.Background(), testSources, false); err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	if source.calls != 7 {
+		t.Fatalf("want a fresh run after completion, got %d calls", source.calls)
+	}
+}
+
+// 目的: 対応表の保存パスと一覧URLを検証し、同梱の対応表の雛形が読み込めることを確認する。副作用: ファイル読み込みを行う。前提: 一覧URLはLodestoneのアチーブメント一覧に限る。
+func TestParseConfig_ValidatesEntries(t *testing.T) {
+	valid := `{"categories":[{"route":"battle","category":"dungeons","listUrl":"https://jp.finalfantasyxiv.com/lodestone/playguide/db/achievement/?page=1"},{"route":"pvp","category":"general","listUrl":""}]}`
+	if _, err := ParseConfig([]byte(valid)); err != nil {
+		t.Fatalf("want valid config, got %v", err)
+	}
+	if _, err := LoadConfig("../../cmd/achievement-crawler/categories.example.json"); err != nil {
+		t.Fatalf("want example config valid, got %v", err)
+	}
+	invalids := []string{
+		`{"categories":[]}`,
+		`{"categories":[{"route":"../tag","category":"tag","listUrl":""}]}`,
+		`{"categories":[{"route":"battle","category":"dungeons","listUrl":""},{"route":"battle","category":"dungeons","listUrl":""}]}`,
+		`{"categories":[{"route":"battle","category":"dungeons","listUrl":"https://example.com/lodestone/playguide/db/achievement/"}]}`,
+		`{"categories":[{"route":"battle","category":"dungeons","listUrl":"https://jp.finalfantasyxiv.com/lodestone/playguide/db/item/"}]}`,
+	}
+	for _, body := range invalids {
+		if _, err := ParseConfig([]byte(body)); err == nil {
+			t.Fatalf("want error for %s, got nil", body)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package masterdata
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ff14/achievement-backend/internal/lodestone"
+)
+
+// DefaultCategoryMenuURL はカテゴリ一覧URLの導出に使う一覧ページ。route/categoryのキーは英語表記から作られているため英語版を使う。
+const DefaultCategoryMenuURL = "https://na.finalfantasyxiv.com/lodestone/playguide/db/achievement/"
+
+var (
+	// 表示名末尾の件数表記（`Battle (123)`等）。
+	categoryCountSuffixRegexp  = regexp.MustCompile(`\s*\([0-9,.\s]+\)$`)
+	categoryKeySeparatorRegexp = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// 目的: listUrl未設定のカテゴリへ、一覧ページの絞り込みリンクのうち種別名・カテゴリ名がroute/categoryと一致するURLを設定する。副作用: 外部サイトへHTTPアクセスする。前提: menuURLは英語表記の一覧ページで、一致しないカテゴリはlistUrl空のまま（unmapped）とする。lodestoneNameがある場合はcategoryの代わりに照合する。
+func ResolveListURLs(ctx context.Context, source Source, menuURL string, sources []CategorySource) ([]CategorySource, error) {
+	resolved := append([]CategorySource(nil), sources...)
+	unresolved := false
+	for _, categorySource := range resolved {
+		if categorySource.ListURL == "" {
+			unresolved = true
+		}
+	}
+	if !unresolved {
+		return resolved, nil
+	}
+	menu, err := source.FetchAchievementDBList(ctx, menuURL)
+	if err != nil {
+		return nil, fmt.Errorf("category menu %s: %w", menuURL, err)
+	}
+	links := append([]lodestone.AchievementDBCategoryLink(nil), menu.Categories...)
+	// 一覧トップに種別配下のカテゴリが表示されない場合は、種別ページを開いて取得する。
+	for _, kindLink := range menu.Categories {
+		if kindLink.CategoryID != "" || hasCategoryLinks(links, kindLink.KindID) {
+			continue
+		}
+		kindPage, err := source.FetchAchievementDBList(ctx, kindLink.URL)
+		if err != nil {
+			return nil, fmt.Errorf("category menu %s: %w", kindLink.URL, err)
+		}
+		links = append(links, kindPage.Categories...)
+	}
+	listURLs := buildCategoryListURLs(links)
+	for index, categorySource := range resolved {
+		if categorySource.ListURL != "" {
+			continue
+		}
+		categoryKey := categorySource.Category
+		if categorySource.LodestoneName != "" {
+			categoryKey = normalizeCategoryKey(categorySource.LodestoneName)
+		}
+		resolved[index].ListURL = listURLs[categorySource.Route+"/"+categoryKey]
+	}
+	return resolved, nil
+}
+
+// 目的: 種別配下のカテゴリリンクが既にあるか判定する。副作用: なし。前提: なし。
+func hasCategoryLinks(links []lodestone.AchievementDBCategoryLink, kindID string) bool {
+	for _, link := range links {
+		if link.KindID == kindID && link.CategoryID != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// 目的: 絞り込みリンクから`route/category`形式のキーと一覧URLの対応を作る。副作用: なし。前提: routeは同じcategory2を持つ種別リンクの表示名から作る。
+func buildCategoryListURLs(links []lodestone.AchievementDBCategoryLink) map[string]string {
+	routes := map[string]string{}
+	for _, link := range links {
+		if link.CategoryID == "" {
+			routes[link.KindID] = normalizeCategoryKey(link.Name)
+		}
+	}
+	listURLs := map[string]string{}
+	for _, link := range links {
+		route, ok := routes[link.KindID]
+		if !ok || link.CategoryID == "" {
+			continue
+		}
+		key := route + "/" + normalizeCategoryKey(link.Name)
+		if _, exists := listURLs[key]; !exists {
+			listURLs[key] = link.URL
+		}
+	}
+	return listURLs
+}
+
+// 目的: 英語の表示名をroute/categoryと同じ命名規則のキーへ変換する。副作用: なし。前提: アポストロフィは詰め、`&`や空白等は`_`区切りとする（例: `Abalathia's Spine`→`abalathias_spine`）。
+func normalizeCategoryKey(name string) string {
+	key := strings.ToLower(categoryCountSuffixRegexp.ReplaceAllString(strings.TrimSpace(name), ""))
+	key = strings.NewReplacer("'", "", "’", "").Replace(key)
+	return strings.Trim(categoryKeySeparatorRegexp.ReplaceAllString(key, "_"), "_")
+}
@@ -0,0 +1,98 @@
+package masterdata
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ff14/achievement-backend/internal/lodestone"
+)
+
+const testMenuURL = "menu"
+
+// 目的: 一覧トップに種別リンクのみがある場合も種別ページからカテゴリリンクを取得し、設定済みのlistUrlは維持して導出することを検証する。副作用: なし。前提: 一致しないカテゴリはlistUrl空のまま残る。
+func TestResolveListURLs_DerivesFromCategoryLinks(t *testing.T) {
+	source := &stubSource{lists: map[string]*lodestone.AchievementDBList{
+		testMenuURL: {Categories: []lodestone.AchievementDBCategoryLink{
+			{KindID: "1", Name: "Battle (1,234)", URL: "menu?category2=1"},
+			{KindID: "3", Name: "Quests", URL: "menu?category2=3"},
+			{KindID: "3", CategoryID: "20", Name: "Quests", URL: "menu?category2=3&category3=20"},
+		}},
+		"menu?category2=1": {Categories: []lodestone.AchievementDBCategoryLink{
+			{KindID: "1", CategoryID: "4", Name: "Dungeons", URL: "menu?category2=1&category3=4"},
+			{KindID: "1", CategoryID: "9", Name: "Field Operations", URL: "menu?category2=1&category3=9"},
+		}},
+	}}
+	sources := []CategorySource{
+		{Route: "battle", Category: "dungeons"},
+		{Route: "battle", Category: "field_operations", ListURL: "https://na.finalfantasyxiv.com/lodestone/playguide/db/achievement/?category2=1&category3=99"},
+		{Route: "quests", Category: "quest", LodestoneName: "Quests"},
+		{Route: "pvp", Category: "frontline"},
+	}
+	resolved, err := ResolveListURLs(context.Background(), source, testMenuURL, sources)
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	want := []string{
+		"menu?category2=1&category3=4",
+		"https://na.finalfantasyxiv.com/lodestone/playguide/db/achievement/?category2=1&category3=99",
+		"menu?category2=3&category3=20",
+		"",
+	}
+	for index, wantURL := range want {
+		if resolved[index].ListURL != wantURL {
+			t.Fatalf("%s: want %q, got %q", resolved[index].key(), wantURL, resolved[index].ListURL)
+		}
+	}
+	if sources[0].ListURL != "" {
+		t.Fatalf("want input sources to be left untouched, got %+v", sources[0])
+	}
+}
+
+// 目的: 同梱の対応表の全カテゴリが、ゲーム内アチーブメント画面と同じ英語の種別名・カテゴリ名の絞り込みリンクから導出できることを検証する。副作用: 同梱の対応表ファイルを読み込む。前提: 種別IDはlodestoneパッケージの種別ページIDに合わせ、カテゴリIDは照合に影響しないため連番とする。
+func TestResolveListURLs_ShippedConfigHasNoUnmappedCategories(t *testing.T) {
+	config, err := LoadConfig("../../cmd/achievement-crawler/categories.example.json")
+	if err != nil {
+		t.Fatalf("failed to load shipped config: %v", err)
+	}
+	menu := []struct {
+		kindID     int
+		kind       string
+		categories []string
+	}{
+		{1, "Battle", []string{"Battle", "Dungeons", "Trials", "Raids", "The Hunt", "Treasure Hunt", "Field Operations"}},
+		{2, "PvP", []string{"General", "Ranking", "The Wolves' Den", "Frontline", "Rival Wings"}},
+		{3, "Quests", []string{"Quests", "Levequests", "Allied Society Quests", "Seasonal Events"}},
+		{4, "Character", []string{"General", "Commendation", "Disciples of War", "Disciples of Magic", "Disciples of the Hand", "Disciples of the Land", "Gold Saucer"}},
+		{5, "Items", []string{"Items", "Materia", "Desynthesis", "Currency", "Collectables", "Relic Weapons", "Zodiac Weapons", "Anima Weapons", "Eureka Weapons", "Resistance Weapons", "Skysteel Tools", "Deep Dungeon Weapons"}},
+		{6, "Crafting & Gathering", []string{"All Disciplines", "Carpenter", "Blacksmith", "Armorer", "Goldsmith", "Leatherworker", "Weaver", "Alchemist", "Culinarian", "Miner", "Botanist", "Fisher"}},
+		{11, "Exploration", []string{"La Noscea", "The Black Shroud", "Thanalan", "Coerthas", "Mor Dhona", "Abalathia's Spine", "Dravania", "Gyr Abania", "Othard", "Norvrandt", "Sightseeing Log", "Duty"}},
+		{12, "Grand Company", []string{"Grand Company", "Maelstrom", "Order of the Twin Adder", "Immortal Flames"}},
+		{13, "Legacy", []string{"Battle", "Dungeons", "Exploration", "Quests", "Gathering", "Grand Company", "Currency", "Seasonal Events"}},
+	}
+	links := []lodestone.AchievementDBCategoryLink{}
+	categoryID := 0
+	for _, kind := range menu {
+		kindID := fmt.Sprint(kind.kindID)
+		links = append(links, lodestone.AchievementDBCategoryLink{KindID: kindID, Name: kind.kind, URL: "menu?category2=" + kindID})
+		for _, name := range kind.categories {
+			categoryID++
+			links = append(links, lodestone.AchievementDBCategoryLink{
+				KindID:     kindID,
+				CategoryID: fmt.Sprint(categoryID),
+				Name:       name,
+				URL:        fmt.Sprintf("menu?category2=%s&category3=%d", kindID, categoryID),
+			})
+		}
+	}
+	source := &stubSource{lists: map[string]*lodestone.AchievementDBList{testMenuURL: {Categories: links}}}
+	resolved, err := ResolveListURLs(context.Background(), source, testMenuURL, config.Categories)
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	for _, categorySource := range resolved {
+		if categorySource.ListURL == "" {
+			t.Fatalf("want list url for %s, got unmapped", categorySource.key())
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package masterdata
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// crawlState は再開と差分集計のためにローカルへ保存する状態。Runは途中で中断した実行、Categoriesは前回保存した内容の要約。
+type crawlState struct {
+	Run        *runState                               `json:"run,omitempty"`
+	Categories map[string]map[string]achievementDigest `json:"categories"`
+}
+
+// runState は実行中のクロール。Completedは保存まで終えたカテゴリの集計で、再開時はこれらを取得し直さない。
+type runState struct {
+	StartedAt time.Time                  `json:"startedAt"`
+	Completed map[string]CategorySummary `json:"completed"`
+}
+
+// achievementDigest はアチーブメント1件の変更検知用の要約。削除時の表示のため名称も保持する。
+type achievementDigest struct {
+	Title string `json:"title"`
+	Hash  string `json:"hash"`
+}
+
+// 目的: 状態ファイルを読み込む。副作用: ファイル読み込みを行う。前提: ファイルが無い場合は空の状態を返す。
+func loadState(path string) (*crawlState, error) {
+	state := &crawlState{Categories: map[string]map[string]achievementDigest{}}
+	body, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(body, state); err != nil {
+		return nil, err
+	}
+	if state.Categories == nil {
+		state.Categories = map[string]map[string]achievementDigest{}
+	}
+	return state, nil
+}
+
+// 目的: 状態ファイルを一時ファイル経由で置き換える。副作用: ファイルを書き込む。前提: 書き込み途中で中断しても前回の状態が残る。
+func saveState(path string, state *crawlState) error {
+	body, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	tempFile, err := os.CreateTemp(filepath.Dir(path), ".crawl-state-*")
+	if err != nil {
+		return err
+	}
+	_, writeErr := tempFile.Write(body)
+	closeErr := tempFile.Close()
+	if writeErr != nil || closeErr != nil {
+		_ = os.Remove(tempFile.Name())
+		return errors.Join(writeErr, closeErr)
+	}
+	if err := os.Rename(tempFile.Name(), path); err != nil {
+		_ = os.Remove(tempFile.Name())
+		return err
+	}
+	return nil
+}
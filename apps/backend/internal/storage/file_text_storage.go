@@ -3,9 +3,13 @@ package storage
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/ff14/achievement-backend/internal/apperrors"
 )
 
 type FileTextStorage struct {
@@ -29,33 +33,54 @@ func NewFileTextStorage(baseDir string) (*FileTextStorage, error) {
 
 // 目的: 相対パス配下へテキストを保存する。副作用: ディレクトリ作成とファイル上書きを行う。前提: relativePathはbaseDir配下を指す相対パスである。
 func (s *FileTextStorage) SaveText(ctx context.Context, relativePath string, body []byte) error {
+	absTargetPath, err := s.resolvePath(ctx, relativePath)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(absTargetPath), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(absTargetPath, body, 0o644)
+}
+
+// 目的: 相対パス配下のテキストを読み込む。副作用: ファイルを読み込む。前提: ファイルが無い場合はapperrors.ErrStorageNotFoundを返す。
+func (s *FileTextStorage) ReadText(ctx context.Context, relativePath string) ([]byte, error) {
+	absTargetPath, err := s.resolvePath(ctx, relativePath)
+	if err != nil {
+		return nil, err
+	}
+	body, err := os.ReadFile(absTargetPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", apperrors.ErrStorageNotFound, relativePath)
+	}
+	return body, err
+}
+
+// 目的: 相対パスをbaseDir配下の絶対パスへ解決する。副作用: なし。前提: コンテキスト終了済み、空パス、baseDir外を指すパスはエラーとする。
+func (s *FileTextStorage) resolvePath(ctx context.Context, relativePath string) (string, error) {
 	select {
 	case <-ctx.Done():
-		return ctx.Err()
+		return "", ctx.Err()
 	default:
 	}
 	if strings.TrimSpace(relativePath) == "" {
-		return errors.New("relativePath is required")
+		return "", errors.New("relativePath is required")
 	}
 
 	cleanRelativePath := filepath.Clean(relativePath)
 	targetPath := filepath.Join(s.baseDir, cleanRelativePath)
 	absTargetPath, err := filepath.Abs(targetPath)
 	if err != nil {
-		return err
+		return "", err
 	}
 	rel, err := filepath.Rel(s.baseDir, absTargetPath)
 	if err != nil {
-		return err
+		return "", err
 	}
 	if strings.HasPrefix(rel, "..") || rel == "." && cleanRelativePath == "." {
-		return errors.New("relativePath must stay inside baseDir")
+		return "", errors.New("relativePath must stay inside baseDir")
 	}
-
-	if err := os.MkdirAll(filepath.Dir(absTargetPath), 0o755); err != nil {
-		return err
-	}
-	return os.WriteFile(absTargetPath, body, 0o644)
+	return absTargetPath, nil
 }
 
 // 目的: 相対パス配下へバイナリを保存する。副作用: ディレクトリ作成とファイル上書きを行う。前提: relativePathはbaseDir配下を指す相対パスである。
@@ -2,9 +2,12 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/ff14/achievement-backend/internal/apperrors"
 )
 
 // 目的: SaveTextがディレクトリを自動作成して保存できることを検証する。副作用: 一時ディレクトリ配下へファイルを書き込む。前提: relativePathは相対パスである。
@@ -43,3 +46,25 @@ func TestFileTextStorage_SaveText_RejectsPathTraversal(t *testing.T) {
 		t.Fatalf("want error, got nil")
 	}
 }
+
+// 目的: ReadTextが保存済みファイルを読み込み、未保存をErrStorageNotFoundで返すことを検証する。副作用: 一時ディレクトリ配下へファイルを書き込む。前提: relativePathは相対パスである。
+func TestFileTextStorage_ReadText_ReturnsBodyOrNotFound(t *testing.T) {
+	fileStorage, err := NewFileTextStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+	if err := fileStorage.SaveText(context.Background(), "patch/patch.json", []byte("[]")); err != nil {
+		t.Fatalf("want no error, got %v", err)
+	}
+
+	body, err := fileStorage.ReadText(context.Background(), "patch/patch.json")
+	if err != nil || string(body) != "[]" {
+		t.Fatalf("want saved body, got %q (%v)", body, err)
+	}
+	if _, err := fileStorage.ReadText(context.Background(), "tag/tag.json"); !errors.Is(err, apperrors.ErrStorageNotFound) {
+		t.Fatalf("want ErrStorageNotFound, got %v", err)
+	}
+	if _, err := fileStorage.ReadText(context.Background(), "../outside.json"); err == nil {
+		t.Fatalf("want error, got nil")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"path/filepath"
 	"strings"
@@ -15,6 +16,7 @@ import (
 
 type objectUploader interface {
 	UploadObject(ctx context.Context, objectPath string, body []byte, contentType string) error
+	DownloadObject(ctx context.Context, objectPath string) ([]byte, error)
 }
 
 type gcsBucketUploader struct {
@@ -44,6 +46,19 @@ func (u *gcsBucketUploader) UploadObject(ctx context.Context, objectPath string,
 	return writer.Close()
 }
 
+// 目的: Cloud Storageからオブジェクトを読み込む。副作用: GCSから読み込みを行う。前提: オブジェクトが無い場合はapperrors.ErrStorageNotFoundを返す。
+func (u *gcsBucketUploader) DownloadObject(ctx context.Context, objectPath string) ([]byte, error) {
+	reader, err := u.bucket.Object(objectPath).NewReader(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil, fmt.Errorf("%w: %s", apperrors.ErrStorageNotFound, objectPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
 // 目的: Cloud Storage保存用ストレージを生成する。副作用: GCSクライアントを初期化する。前提: bucketNameは空文字ではない。
 func NewGCSStorage(ctx context.Context, bucketName string, objectPrefix string) (*GCSStorage, error) {
 	if strings.TrimSpace(bucketName) == "" {
@@ -82,6 +97,21 @@ func (s *GCSStorage) SaveBinary(ctx context.Context, path string, body []byte, c
 	return s.save(ctx, path, body, resolvedContentType)
 }
 
+// 目的: Cloud Storageからテキストを読み込む。副作用: GCSから読み込みを行う。前提: pathは相対パスで、権限不足はErrPermissionDeniedへ正規化する。
+func (s *GCSStorage) ReadText(ctx context.Context, path string) ([]byte, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, errors.New("path is required")
+	}
+	body, err := s.uploader.DownloadObject(ctx, s.resolveObjectPath(path))
+	if err != nil {
+		if !errors.Is(err, apperrors.ErrStorageNotFound) && isPermissionDeniedError(err) {
+			return nil, fmt.Errorf("%w: %v", apperrors.ErrPermissionDenied, err)
+		}
+		return nil, err
+	}
+	return body, nil
+}
+
 // 目的: 共通のCloud Storage保存処理を提供する。副作用: GCSへ書き込みを行う。前提: pathはオブジェクトパスへ正規化可能である。
 func (s *GCSStorage) save(ctx context.Context, path string, body []byte, contentType string) error {
 	if strings.TrimSpace(path) == "" {
@@ -13,6 +13,7 @@ type stubObjectUploader struct {
 	savedPath        string
 	savedBody        []byte
 	savedContentType string
+	objects          map[string][]byte
 	err              error
 }
 
@@ -24,6 +25,32 @@ func (s *stubObjectUploader) UploadObject(_ context.Context, objectPath string,
 	return s.err
 }
 
+// 目的: テスト用ダウンロード処理を差し替える。副作用: なし。前提: objectsに無いパスは未保存として扱う。
+func (s *stubObjectUploader) DownloadObject(_ context.Context, objectPath string) ([]byte, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	body, ok := s.objects[objectPath]
+	if !ok {
+		return nil, apperrors.ErrStorageNotFound
+	}
+	return body, nil
+}
+
+// 目的: ReadTextがprefix付きパスから読み込み、未保存をErrStorageNotFoundで返すことを検証する。副作用: なし。前提: uploaderにオブジェクトを登録済みである。
+func TestGCSStorage_ReadText_WithPrefix(t *testing.T) {
+	uploader := &stubObjectUploader{objects: map[string][]byte{"forfan-resource/patch/patch.json": []byte("[]")}}
+	storage := newGCSStorageForTest(uploader, "forfan-resource")
+
+	body, err := storage.ReadText(context.Background(), "patch/patch.json")
+	if err != nil || string(body) != "[]" {
+		t.Fatalf("want stored body, got %q (%v)", body, err)
+	}
+	if _, err := storage.ReadText(context.Background(), "tag/tag.json"); !errors.Is(err, apperrors.ErrStorageNotFound) {
+		t.Fatalf("want ErrStorageNotFound, got %v", err)
+	}
+}
+
 // 目的: SaveTextがprefix付きパスで保存されることを検証する。副作用: なし。前提: uploaderが正常に保存できる。
 func TestGCSStorage_SaveText_WithPrefix(t *testing.T) {
 	uploader := &stubObjectUploader{}
@@ -25,12 +25,26 @@ interface CharacterInfo {
   gender: '♂' | '♀'
   birthDay: string
   birthMonth: string
+  guardian?: string
+  cityState?: string
+  grandCompany?: GrandCompany
+  title?: string
+  portraitImageUrl?: string
+  portraitImagePath?: string
+  faceImageUrl?: string
+  faceImagePath?: string
   // updatedDate: Date
   battleRoles: import('@murofush/forfan-common-package/lib/types').BattleRoles<Level>
   crafter: import('@murofush/forfan-common-package/lib/types').Crafter<Level>
   gatherer: import('@murofush/forfan-common-package/lib/types').Gatherer<Level>
 }
 
+interface GrandCompany {
+  name: string
+  rank: string
+  rankIconUrl: string
+}
+
 interface Level {
   level: number
 }